@@ -2,22 +2,69 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"github.com/worldiety/gdoc/internal/app"
 	"log"
 	"os"
 	"os/exec"
+	"path/filepath"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "diff":
+			diff(os.Args[2:])
+			return
+		case "compat":
+			compat(os.Args[2:])
+			return
+		case "deprecated":
+			deprecated(os.Args[2:])
+			return
+		case "coverage":
+			coverage(os.Args[2:])
+			return
+		case "show":
+			show(os.Args[2:])
+			return
+		case "serve":
+			serve(os.Args[2:])
+			return
+		}
+	}
+
 	var cfg app.Config
 	cfg.Reset()
 	cfg.Flags(flag.CommandLine)
 	flag.Parse()
 
-	buf, err := app.Apply(cfg)
+	if cfg.Versions != "" {
+		versions(cfg)
+		return
+	}
+
+	node, err := app.Load(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	buf, err := app.Render(cfg, node)
+	if err != nil {
+		panic(err)
+	}
+
+	files, err := app.Files(cfg, node)
 	if err != nil {
 		panic(err)
 	}
+
+	for name, content := range files {
+		if err := os.WriteFile(name, content, 0644); err != nil {
+			log.Printf("could not write to file '%s'\nerror: %e", name, err)
+		}
+	}
+
 	switch cfg.OutputFormat {
 	case app.Adoc, app.Html, app.Pdf:
 		file, err := os.Create("doc.adoc")
@@ -31,27 +78,194 @@ func main() {
 		}
 
 		if cfg.OutputFormat == app.Html {
-			err = RenderToHtml(file.Name())
+			err = RenderToHtml(file.Name(), cfg.DiagramExtension())
 			if err != nil {
 				log.Printf("file could not be created\nerror: %e", err)
 			}
 		}
 		if cfg.OutputFormat == app.Pdf {
-			err = RenderToPdf(file.Name())
+			err = RenderToPdf(file.Name(), cfg.DiagramExtension())
 			if err != nil {
 				log.Printf("file could not be created\nerror: %e", err)
 			}
 		}
+	case app.Native:
+		if err := os.WriteFile("index.html", buf, 0644); err != nil {
+			log.Printf("could not write to file 'index.html'\nerror: %e", err)
+		}
+	case app.Json, app.Yaml:
+		name := "doc." + cfg.OutputFormat
+		if err := os.WriteFile(name, buf, 0644); err != nil {
+			log.Printf("could not write to file '%s'\nerror: %e", name, err)
+		}
 	default:
 		log.Print("no output file")
 	}
 }
 
+// versions writes the native html documentation of each configured version into the output directory
+func versions(cfg app.Config) {
+	files, err := app.Versions(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for name, content := range files {
+		name = filepath.Join(cfg.Out, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(name, content, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// diff prints the API changes between two git refs, e.g. gdoc diff v1.0.0 HEAD
+func diff(args []string) {
+	var cfg app.DiffConfig
+	cfg.Reset()
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	cfg.Flags(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: gdoc diff [flags] <old ref> <new ref>")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	cfg.Old, cfg.New = flags.Arg(0), flags.Arg(1)
+
+	buf, err := app.Diff(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, _ = os.Stdout.Write(buf)
+}
+
+// compat checks the working tree against the previous version and exits with 1, if the changes are not allowed
+func compat(args []string) {
+	var cfg app.CompatConfig
+	cfg.Reset()
+	flags := flag.NewFlagSet("compat", flag.ExitOnError)
+	cfg.Flags(flags)
+	_ = flags.Parse(args)
+
+	report, err := app.Compat(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	buf, err := app.RenderCompat(cfg, report)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, _ = os.Stdout.Write(buf)
+	if !report.Allowed {
+		os.Exit(1)
+	}
+}
+
+// deprecated prints all usages of deprecated declarations within the module and exits with 1, if there are any
+func deprecated(args []string) {
+	var cfg app.Config
+	cfg.Reset()
+	flags := flag.NewFlagSet("deprecated", flag.ExitOnError)
+	cfg.Flags(flags)
+	_ = flags.Parse(args)
+
+	usages, err := app.DeprecatedUsages(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, u := range usages {
+		fmt.Println(u)
+	}
+
+	if len(usages) > 0 {
+		os.Exit(1)
+	}
+}
+
+// coverage prints the documentation coverage and exits with 1, if it is below the configured minimum
+func coverage(args []string) {
+	var cfg app.CoverageConfig
+	cfg.Reset()
+	flags := flag.NewFlagSet("coverage", flag.ExitOnError)
+	cfg.Flags(flags)
+	_ = flags.Parse(args)
+
+	report, err := app.Coverage(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	buf, err := app.RenderCoverage(cfg, report)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, _ = os.Stdout.Write(buf)
+
+	if cfg.Badge != "" {
+		if err := os.WriteFile(cfg.Badge, app.CoverageBadge(report), 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if report.Total.Percent() < cfg.Min {
+		fmt.Fprintf(os.Stderr, "documentation coverage %.1f%% is below the required %.1f%%\n", report.Total.Percent(), cfg.Min)
+		os.Exit(1)
+	}
+}
+
+// show prints the documentation of a package or symbol to the terminal, e.g. gdoc show api.Module.Packages
+func show(args []string) {
+	var cfg app.ShowConfig
+	cfg.Reset()
+	flags := flag.NewFlagSet("show", flag.ExitOnError)
+	cfg.Flags(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: gdoc show [flags] <pkg>[.Symbol[.Member]]")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	buf, err := app.Show(cfg, flags.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, _ = os.Stdout.Write(buf)
+}
+
+// serve starts a local server, which renders the native html documentation and reloads it on changes
+func serve(args []string) {
+	var cfg app.ServeConfig
+	cfg.Reset()
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	cfg.Flags(flags)
+	_ = flags.Parse(args)
+
+	log.Fatal(app.Serve(cfg))
+}
+
 // RenderToHtml loads the file in the given path and uses the asciidoc cli tool to render and save a html file
-func RenderToHtml(adocFilename string) error {
+func RenderToHtml(adocFilename string, diagrams bool) error {
 	htmlFileName := "htmlOutput.html"
 	// use asciidoctor to create a html file from the adoc file
-	cmd := exec.Command("asciidoctor", "-b", "html5", "-o", htmlFileName, adocFilename)
+	cmd := exec.Command("asciidoctor", withDiagramExtension([]string{"-b", "html5", "-o", htmlFileName, adocFilename}, diagrams)...)
 	setupCMD(cmd)
 	err := cmd.Run()
 	if err != nil {
@@ -61,10 +275,10 @@ func RenderToHtml(adocFilename string) error {
 }
 
 // RenderToPdf takes a filename of an adoc file and uses asciidoc-pdf to render and save a pdf file
-func RenderToPdf(adocFileName string) error {
+func RenderToPdf(adocFileName string, diagrams bool) error {
 	// Use the asciidoctor-pdf library to generate a PDF from the adoc file
 	// get commands from command line and export errors to it
-	cmd := exec.Command("asciidoctor-pdf", adocFileName)
+	cmd := exec.Command("asciidoctor-pdf", withDiagramExtension([]string{adocFileName}, diagrams)...)
 	setupCMD(cmd)
 
 	err := cmd.Run()
@@ -74,6 +288,14 @@ func RenderToPdf(adocFileName string) error {
 	return nil
 }
 
+// withDiagramExtension requires asciidoctor-diagram to render the embedded diagram blocks, if necessary.
+func withDiagramExtension(args []string, diagrams bool) []string {
+	if diagrams {
+		return append([]string{"-r", "asciidoctor-diagram"}, args...)
+	}
+	return args
+}
+
 func setupCMD(cmd *exec.Cmd) {
 	cmd.Stdout = os.Stdout
 	cmd.Stdin = os.Stdin
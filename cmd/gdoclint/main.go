@@ -0,0 +1,12 @@
+// Command gdoclint checks doc comments like go vet, e.g. gdoclint ./...
+// Use -fix to apply the suggested fixes.
+package main
+
+import (
+	"github.com/worldiety/gdoc/pkg/doclint"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(doclint.Analyzer)
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// AnchorScheme derives the anchor of a declaration from its identity, see Module Anchor.
+type AnchorScheme interface {
+	Anchor(id RefId) string
+}
+
+// HashAnchors is the default scheme, which hashes the import path and the identifier into anchors like gd4f1c….
+// Packages have no identifier, see RefId Package.
+// Its anchors never collide, but are not human-readable.
+type HashAnchors struct{}
+
+func (a HashAnchors) Anchor(id RefId) string {
+	return id.LegacyID()
+}
+
+// ReadableAnchors joins the import path relative to Module and the identifier, like internal-api--Symbol--Name.
+// The anchors may collide, e.g. for import paths which contain dashes, thus use the scheme through NewAnchors.
+type ReadableAnchors struct {
+	Module string // the import path of the module, whose packages are named relative to it
+}
+
+func (a ReadableAnchors) Anchor(id RefId) string {
+	path := id.ImportPath
+	switch {
+	case path == a.Module:
+		path = id.PackageName()
+	case strings.HasPrefix(path, a.Module+"/"):
+		path = strings.TrimPrefix(path, a.Module+"/")
+	}
+
+	anchor := strings.ReplaceAll(path, "/", "-")
+	if id.Identifier != "" {
+		anchor += "--" + strings.ReplaceAll(id.Identifier, ".", "--")
+	}
+
+	anchor = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.' {
+			return r
+		}
+		return '_' // not valid in asciidoc anchors
+	}, anchor)
+
+	if r, _ := utf8.DecodeRuneInString(anchor); !unicode.IsLetter(r) {
+		anchor = "_" + anchor
+	}
+
+	return anchor
+}
+
+// Anchors assigns an anchor to each package and symbol of a set of modules, so that no two of them share an anchor.
+// Symbols, which are not declared by any of the modules, get the anchor of the underlying scheme.
+type Anchors struct {
+	scheme  AnchorScheme
+	anchors map[RefId]string
+}
+
+// NewAnchors applies the scheme to the packages and symbols of all given modules, which are usually the versions of
+// the same module. The packages are assigned first in the order of their import paths, then the symbols in the order
+// of Module Symbols. Colliding anchors get a suffix from the hash of their identity and, if still taken, a number.
+func NewAnchors(scheme AnchorScheme, modules ...*Module) *Anchors {
+	a := &Anchors{scheme: scheme, anchors: map[RefId]string{}}
+	used := map[string]bool{}
+	add := func(ref RefId) {
+		if _, ok := a.anchors[ref]; ok {
+			return
+		}
+
+		anchor := scheme.Anchor(ref)
+		if used[anchor] {
+			anchor += "-" + ref.LegacyID()[2:10]
+		}
+
+		for i, base := 2, anchor; used[anchor]; i++ {
+			anchor = fmt.Sprintf("%s-%d", base, i)
+		}
+
+		used[anchor] = true
+		a.anchors[ref] = anchor
+	}
+
+	for _, m := range modules {
+		paths := make([]string, 0, len(m.Packages))
+		for path := range m.Packages {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			add(NewRefID(path, ""))
+		}
+	}
+
+	for _, m := range modules {
+		for _, sym := range m.Symbols() {
+			add(sym.Ref)
+		}
+	}
+
+	return a
+}
+
+func (a *Anchors) Anchor(id RefId) string {
+	if anchor, ok := a.anchors[id]; ok {
+		return anchor
+	}
+
+	return a.scheme.Anchor(id)
+}
+
+// Legacy maps the anchors of HashAnchors to the assigned ones, so that published links can be redirected.
+func (a *Anchors) Legacy() map[string]string {
+	res := map[string]string{}
+	for id, anchor := range a.anchors {
+		res[id.LegacyID()] = anchor
+	}
+
+	return res
+}
+
+// Anchor returns the anchor of the declaration in the scheme of the module or the one of HashAnchors, if the
+// module has none.
+func (m *Module) Anchor(id RefId) string {
+	if m.Anchors != nil {
+		return m.Anchors.Anchor(id)
+	}
+
+	return id.LegacyID()
+}
+
+// Package returns the identity of the package, which declares the symbol. Its anchor is the one of the package.
+func (id RefId) Package() RefId {
+	return RefId{ImportPath: id.ImportPath}
+}
+
+// LegacyID returns the anchor of HashAnchors, which never changes.
+func (id RefId) LegacyID() string {
+	tmp := sha256.Sum224([]byte(id.ImportPath + id.Identifier))
+	return "gd" + hex.EncodeToString(tmp[:])
+}
@@ -0,0 +1,59 @@
+package api_test
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/fixture"
+)
+
+func ExampleNewAnchors() {
+	m, err := fixture.Resolve()
+	if err != nil {
+		panic(err)
+	}
+
+	// the packages store-index and store/index have the same readable prefix
+	anchors := api.NewAnchors(api.ReadableAnchors{Module: m.Name}, m)
+	fmt.Println(anchors.Anchor(api.NewRefID(fixture.Name+"/store-index", "Index")))
+	fmt.Println(anchors.Anchor(api.NewRefID(fixture.Name+"/store/index", "Index")))
+	fmt.Println(anchors.Anchor(api.NewRefID(fixture.Name+"/store/index", "Index.Field")))
+	fmt.Println(anchors.Anchor(api.NewRefID(fixture.Name+"/store", "NewStore")))
+	fmt.Println(anchors.Anchor(api.NewRefID(fixture.Name+"/store-index", "")))
+	fmt.Println(anchors.Anchor(api.NewRefID(fixture.Name+"/store/index", "")))
+	// Output:
+	// store-index--Index
+	// store-index--Index-0d8426c1
+	// store-index--Index--Field
+	// store--NewStore
+	// store-index
+	// store-index-1a8c7429
+}
+
+// fixedAnchors maps the identifiers to anchors, regardless of their package.
+type fixedAnchors map[string]string
+
+func (a fixedAnchors) Anchor(id api.RefId) string {
+	return a[id.Identifier]
+}
+
+func ExampleNewAnchors_collision() {
+	a, b, c := api.NewRefID("m", "A"), api.NewRefID("m", "B"), api.NewRefID("m", "C")
+	m := &api.Module{Name: "m", Packages: map[api.ImportPath]*api.Package{"m": {
+		Name: "m",
+		Functions: map[string]*api.Function{
+			"A": {TypeDefinition: a, Name: "A"},
+			"B": {TypeDefinition: b, Name: "B"},
+			"C": {TypeDefinition: c, Name: "C"},
+		},
+	}}}
+
+	// C collides with A and with its hashed suffix again with B
+	anchors := api.NewAnchors(fixedAnchors{"": "m", "A": "x", "B": "x-" + c.LegacyID()[2:10], "C": "x"}, m)
+	fmt.Println(anchors.Anchor(a))
+	fmt.Println(anchors.Anchor(b) == "x-"+c.LegacyID()[2:10])
+	fmt.Println(anchors.Anchor(c) == "x-"+c.LegacyID()[2:10]+"-2")
+	// Output:
+	// x
+	// true
+	// true
+}
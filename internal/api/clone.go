@@ -0,0 +1,147 @@
+package api
+
+// Clone returns a deep copy of the package, which shares no mutable state with it. Thus, a parsed package can be
+// resolved repeatedly, although resolving modifies it. The values of the constants are shared, because they are
+// never modified.
+func (p *Package) Clone() *Package {
+	res := *p
+	res.Imports = append(Imports(nil), p.Imports...)
+	res.Stereotypes = append([]Stereotype(nil), p.Stereotypes...)
+	res.Deprecated = cloneDeprecation(p.Deprecated)
+	res.Terms = append([]Term(nil), p.Terms...)
+
+	res.Types = make(map[string]RefId, len(p.Types))
+	for name, ref := range p.Types {
+		res.Types[name] = ref
+	}
+
+	res.Consts = nil
+	for _, block := range p.Consts {
+		content := make([]Constant, 0, len(block.Content))
+		for _, c := range block.Content {
+			c.Deprecated = cloneDeprecation(c.Deprecated)
+			content = append(content, c)
+		}
+		res.Consts = append(res.Consts, NewConstantBlock(content, block.Doc))
+	}
+
+	res.Vars = make(map[string]*Variable, len(p.Vars))
+	for name, v := range p.Vars {
+		res.Vars[name] = (*Variable)(cloneField((*Field)(v), nil, nil))
+	}
+
+	res.Functions = make(map[string]*Function, len(p.Functions))
+	for name, fn := range p.Functions {
+		res.Functions[name] = cloneFunction(fn)
+	}
+
+	res.Structs = make(map[string]*Struct, len(p.Structs))
+	for name, s := range p.Structs {
+		res.Structs[name] = cloneStruct(s)
+	}
+
+	return &res
+}
+
+func cloneStruct(s *Struct) *Struct {
+	res := *s
+	res.Stereotypes = append([]Stereotype(nil), s.Stereotypes...)
+	res.Implements = append([]RefId(nil), s.Implements...)
+	res.ReferencedBy = append([]Reference(nil), s.ReferencedBy...)
+	res.Deprecated = cloneDeprecation(s.Deprecated)
+
+	res.Fields = nil
+	for _, f := range s.Fields {
+		res.Fields = append(res.Fields, cloneField(f, s, &res))
+	}
+
+	res.Generics = nil
+	for _, g := range s.Generics {
+		res.Generics = append(res.Generics, cloneField(g, s, &res))
+	}
+
+	res.Methods = nil
+	for _, m := range s.Methods {
+		method := &Method{Function: cloneFunction(m.Function)}
+		if m.Recv != nil {
+			recv := *m.Recv
+			recv.Field = cloneField(m.Recv.Field, s, &res)
+			method.Recv = &recv
+		}
+		res.Methods = append(res.Methods, method)
+	}
+
+	res.Constructors = nil
+	for _, fn := range s.Constructors {
+		res.Constructors = append(res.Constructors, cloneFunction(fn))
+	}
+
+	return &res
+}
+
+func cloneFunction(fn *Function) *Function {
+	if fn == nil {
+		return nil
+	}
+
+	res := *fn
+	res.Calls = append([]CallRef(nil), fn.Calls...)
+	res.CalledBy = append([]CallRef(nil), fn.CalledBy...)
+	res.Deprecated = cloneDeprecation(fn.Deprecated)
+	res.Parameters = cloneFields(fn.Parameters)
+	res.Results = cloneFields(fn.Results)
+	res.Generics = nil
+	for _, g := range fn.Generics {
+		res.Generics = append(res.Generics, cloneField(g, nil, nil))
+	}
+	return &res
+}
+
+func cloneFields(fields map[string]*Field) map[string]*Field {
+	if fields == nil {
+		return nil
+	}
+
+	res := make(map[string]*Field, len(fields))
+	for name, f := range fields {
+		res[name] = cloneField(f, nil, nil)
+	}
+	return res
+}
+
+// cloneField copies the field and replaces its parent struct old by parent.
+func cloneField(f *Field, old, parent *Struct) *Field {
+	if f == nil {
+		return nil
+	}
+
+	res := *f
+	res.TypeDesc = cloneTypeDesc(f.TypeDesc)
+	res.Stereotypes = append([]Stereotype(nil), f.Stereotypes...)
+	res.Deprecated = cloneDeprecation(f.Deprecated)
+	if f.ParentStruct != nil && f.ParentStruct == old {
+		res.ParentStruct = parent
+	}
+	return &res
+}
+
+func cloneTypeDesc(td *TypeDesc) *TypeDesc {
+	if td == nil {
+		return nil
+	}
+
+	res := *td
+	if td.MapType != nil {
+		res.MapType = &MapType{KeyType: cloneTypeDesc(td.MapType.KeyType), ValueType: cloneTypeDesc(td.MapType.ValueType)}
+	}
+	return &res
+}
+
+func cloneDeprecation(d *Deprecation) *Deprecation {
+	if d == nil {
+		return nil
+	}
+
+	res := *d
+	return &res
+}
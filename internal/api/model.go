@@ -1,8 +1,6 @@
 package api
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
 	"regexp"
 	"strings"
@@ -29,11 +27,6 @@ func NewRefID(importPath, identifier string) RefId {
 	}
 }
 
-func (id RefId) ID() string {
-	tmp := sha256.Sum224([]byte(id.ImportPath + id.Identifier))
-	return fmt.Sprintf("gd%s", hex.EncodeToString(tmp[:]))
-}
-
 func (id RefId) Named() bool {
 	return id.Identifier != ""
 }
@@ -63,6 +56,10 @@ const (
 	StereotypeParameterOut    = "out"
 	StereotypeParameterResult = "result"
 	StereotypeGeneric         = "generic"
+	StereotypeInterface       = "interface"
+	StereotypeEmbedded        = "embedded" // a field without name, whose methods are promoted
+	StereotypeVariable        = "variable"
+	StereotypeDeprecated      = "deprecated" // a declaration with a Deprecated: paragraph, see Deprecation
 )
 
 type ImportPath = string
@@ -83,6 +80,8 @@ type Module struct {
 	Readme   string
 	Name     string
 	Packages map[ImportPath]*Package
+	Glossary []Term
+	Anchors  AnchorScheme `json:"-" yaml:"-"` // the anchors of the declarations, see Module Anchor
 }
 
 type List[T, X any, V Constant] struct {
@@ -126,23 +125,83 @@ type Package struct {
 	Name              string
 	Imports           Imports
 	Stereotypes       []Stereotype
+	Deprecated        *Deprecation
+	Position          Position // the package clause of the documented file
 	Types             map[string]RefId
 	Consts            []ConstantBlock
 	Vars              map[string]*Variable
 	Functions         map[string]*Function
 	Structs           map[string]*Struct
+	Terms             []Term `json:"-" yaml:"-"` // the terms of the gdoc:term directives, see Module Glossary
 }
 type Struct struct {
 	TypeDefinition     RefId
 	Comment            string
 	Name               string
+	Stereotypes        []Stereotype
 	Fields             []*Field
 	Methods            []*Method
 	Generics           Generics
 	Constructors       []*Function
+	Implements         []RefId // the interfaces declared in the module, which are realised by this type
+	Sealed             bool    // an interface with unexported methods, which cannot be implemented by other packages
+	ReferencedBy       []Reference
+	Since              string // the first release, which declares this type
+	Deprecated         *Deprecation
+	Position           Position
 	WhiteSpaceInFields int
 }
 
+// Position locates a declaration in the source. File is relative to the module root and uses forward slashes.
+// URL links to the declaration on a code host, if configured.
+type Position struct {
+	File    string
+	Line    int
+	Column  int
+	EndLine int
+	URL     string
+}
+
+// Valid returns true, if the position is known.
+func (p Position) Valid() bool {
+	return p.File != ""
+}
+
+// String formats the position like the go tools do, e.g. api/model.go:12:6.
+func (p Position) String() string {
+	if p.Line == 0 {
+		return p.File
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// Deprecation is the content of a Deprecated: paragraph of a doc comment. Notice usually names the replacement
+// and Since is the first release, which deprecates the declaration.
+type Deprecation struct {
+	Notice string
+	Since  string
+}
+
+// Reference denotes a single usage of a type. From is the declaring struct, function, method or variable and Name is
+// the name of the field, parameter, result or type parameter, which is empty for embedded fields and unnamed results.
+// Kind is one of StereotypeProperty, StereotypeEmbedded, StereotypeParameter, StereotypeParameterResult,
+// StereotypeGeneric or StereotypeVariable.
+type Reference struct {
+	From RefId
+	Kind Stereotype
+	Name string
+}
+
+// Interface returns true, if the type is declared as an interface.
+func (s *Struct) Interface() bool {
+	for _, st := range s.Stereotypes {
+		if st == StereotypeInterface {
+			return true
+		}
+	}
+	return false
+}
+
 type Generics []*Field
 type Method struct {
 	*Function
@@ -170,16 +229,34 @@ type Function struct {
 	Signature      string
 	Parameters     map[string]*Field
 	Results        map[string]*Field
+	Generics       Generics  // the type parameters
+	Calls          []CallRef // the exported functions and methods of the module, which are called by this one
+	CalledBy       []CallRef // the exported functions and methods of the module, which call this one
+	Since          string    // the first release, which declares this function
+	Deprecated     *Deprecation
+	Position       Position
+	Body           string // the source of the declaration starting at the line of Position, if requested
+}
+
+// CallRef refers to an exported function or method of the module. Depth 1 denotes a direct call, otherwise the
+// call happens through Depth-1 intermediate functions.
+type CallRef struct {
+	Target RefId
+	Depth  int
 }
 
 type Field struct {
-	TypeDesc *TypeDesc
-	Name     string
-	Comment  string
-	Doc      string
+	TypeDesc   *TypeDesc
+	Definition RefId // the identity of a struct field or variable, empty for parameters and results
+	Name       string
+	Comment    string
+	Doc        string
 	// ParentStruct test
-	ParentStruct *Struct // the struct, this field is a property of
+	ParentStruct *Struct `json:"-" yaml:"-"` // the struct, this field is a property of
 	Stereotypes  []Stereotype
+	Since        string // the first release, which declares this field or variable
+	Deprecated   *Deprecation
+	Position     Position
 }
 
 func NewField(name, comment string, doc string, t *TypeDesc, parent *Struct) *Field {
@@ -245,15 +322,20 @@ func NewConstantBlock(consts []Constant, doc string) ConstantBlock {
 }
 
 type Constant struct {
-	RefId   RefId
-	Value   any
-	Comment string
+	RefId      RefId
+	Value      any    `json:"-" yaml:"-"`
+	Expr       string // the value as written in the source, empty for implicit repetitions like iota sequences
+	Comment    string
+	Since      string // the first release, which declares this constant
+	Deprecated *Deprecation
+	Position   Position
 }
 
-func NewConstant(refId RefId, comment string, value any) Constant {
+func NewConstant(refId RefId, comment string, value any, expr string) Constant {
 	return Constant{
 		RefId:   refId,
 		Value:   value,
+		Expr:    expr,
 		Comment: comment,
 	}
 }
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"sort"
+	"strings"
+)
+
+// SymbolKind classifies the declaration of a Symbol.
+type SymbolKind string
+
+const (
+	SymbolType   SymbolKind = "type"
+	SymbolFunc   SymbolKind = "func"
+	SymbolMethod SymbolKind = "method"
+	SymbolField  SymbolKind = "field"
+	SymbolConst  SymbolKind = "const"
+	SymbolVar    SymbolKind = "var"
+)
+
+// Symbol is a single exported identifier of a module. Methods and fields are named like Type.Method and the Ref
+// is the identity of their declaration, which is also used as anchor.
+type Symbol struct {
+	Ref        RefId
+	Name       string
+	Kind       SymbolKind
+	Package    ImportPath
+	Deprecated *Deprecation
+}
+
+// Term is an entry of the glossary, either from a glossary file or from a //gdoc:term directive.
+type Term struct {
+	Name       string
+	Definition string
+}
+
+// Symbols returns all exported identifiers of the module, sorted case-insensitively by name and then by import path.
+func (m *Module) Symbols() []Symbol {
+	var res []Symbol
+	add := func(ref RefId, kind SymbolKind, deprecated *Deprecation) {
+		res = append(res, Symbol{Ref: ref, Name: ref.Identifier, Kind: kind, Package: ref.ImportPath, Deprecated: deprecated})
+	}
+
+	for _, p := range m.Packages {
+		for _, block := range p.Consts {
+			for _, c := range block.Content {
+				add(c.RefId, SymbolConst, c.Deprecated)
+			}
+		}
+
+		for _, v := range p.Vars {
+			add(v.Definition, SymbolVar, v.Deprecated)
+		}
+
+		for _, fn := range p.Functions {
+			add(fn.TypeDefinition, SymbolFunc, fn.Deprecated)
+		}
+
+		for _, s := range p.Structs {
+			add(s.TypeDefinition, SymbolType, s.Deprecated)
+			for _, fn := range s.Constructors {
+				add(fn.TypeDefinition, SymbolFunc, fn.Deprecated)
+			}
+			for _, method := range s.Methods {
+				add(method.TypeDefinition, SymbolMethod, method.Deprecated)
+			}
+			for _, f := range s.Fields {
+				if f.Definition.Named() {
+					add(f.Definition, SymbolField, f.Deprecated)
+				}
+			}
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		a, b := strings.ToLower(res[i].Name), strings.ToLower(res[j].Name)
+		if a != b {
+			return a < b
+		}
+		if res[i].Name != res[j].Name {
+			return res[i].Name < res[j].Name
+		}
+		return res[i].Package < res[j].Package
+	})
+
+	return res
+}
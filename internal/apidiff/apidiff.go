@@ -0,0 +1,314 @@
+// Package apidiff compares two parsed versions of a module and reports the changes of its exported API.
+package apidiff
+
+import (
+	"github.com/worldiety/gdoc/internal/api"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+type Kind string
+
+const (
+	Added   Kind = "added"
+	Removed Kind = "removed"
+	Changed Kind = "changed"
+)
+
+// SymbolPackage denotes a whole package in a Change, the other symbols use the api.SymbolKind values.
+const SymbolPackage api.SymbolKind = "package"
+
+// Change describes a single added, removed or changed declaration. Old and New contain the declaration in Go
+// syntax and are empty, if the declaration did not exist in the respective version.
+type Change struct {
+	Kind    Kind
+	Symbol  api.SymbolKind
+	Package api.ImportPath
+	Name    string // like Type.Method or empty for packages
+	Old     string
+	New     string
+	// Breaking is set, if the change may break clients according to the Go compatibility rules and Reason
+	// explains why.
+	Breaking bool
+	Reason   string
+}
+
+// Report contains all changes between two versions of a module, sorted by package and name.
+type Report struct {
+	Module  string
+	Old     string // the old version, e.g. a git ref
+	New     string // the new version
+	Changes []Change
+}
+
+// Packages returns the import paths of all packages with changes in the order of the report.
+func (r *Report) Packages() []api.ImportPath {
+	var res []api.ImportPath
+	for _, c := range r.Changes {
+		if len(res) == 0 || res[len(res)-1] != c.Package {
+			res = append(res, c.Package)
+		}
+	}
+	return res
+}
+
+// Compare returns the changes between both modules. Packages are matched by their import path relative to the
+// module, so that a major version suffix of the module path does not cause spurious changes.
+func Compare(old, new *api.Module) []Change {
+	oldDecls, newDecls := declarations(old), declarations(new)
+
+	var res []Change
+	for key, o := range oldDecls {
+		n, ok := newDecls[key]
+		switch {
+		case !ok:
+			res = append(res, classify(Change{Kind: Removed, Symbol: o.kind, Package: o.pkg, Name: key.name, Old: o.src}, o))
+		case o.kind == n.kind && (o.untyped || n.untyped):
+			// the type of the variable is unknown in one version, thus it cannot be compared
+		case o.compared() != n.compared() || o.kind != n.kind:
+			res = append(res, classify(Change{Kind: Changed, Symbol: n.kind, Package: n.pkg, Name: key.name, Old: o.src, New: n.src}, n))
+		}
+	}
+
+	for key, n := range newDecls {
+		if _, ok := oldDecls[key]; !ok {
+			if owner, _, ok := strings.Cut(key.name, "."); ok && n.iface {
+				// other packages may implement the interface, unless it has been sealed before
+				n.sealed = n.sealed && oldDecls[declKey{path: key.path, name: owner}].sealed
+			}
+			res = append(res, classify(Change{Kind: Added, Symbol: n.kind, Package: n.pkg, Name: key.name, New: n.src}, n))
+		}
+	}
+
+	// members of added or removed packages and types are implied
+	implied := map[declKey]bool{}
+	for _, c := range res {
+		if c.Kind != Changed && (c.Symbol == SymbolPackage || c.Symbol == api.SymbolType) {
+			implied[declKey{path: relPath(c.Package, old, new), name: c.Name}] = true
+		}
+	}
+
+	filtered := res[:0]
+	for _, c := range res {
+		path := relPath(c.Package, old, new)
+		if c.Symbol != SymbolPackage && implied[declKey{path: path}] {
+			continue
+		}
+		if owner, _, ok := strings.Cut(c.Name, "."); ok && implied[declKey{path: path, name: owner}] {
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+	res = filtered
+
+	sort.Slice(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Kind < b.Kind
+	})
+
+	return res
+}
+
+type declKey struct {
+	path string // import path relative to the module
+	name string
+}
+
+type decl struct {
+	kind  api.SymbolKind
+	pkg   api.ImportPath
+	src   string
+	sig   string // the signature of a function or method without parameter names, see signatureTypes
+	iface bool   // a method of an interface
+	// sealed is set for an interface and its methods, if the interface cannot be implemented by other packages.
+	sealed bool
+	// untyped is set for a variable without an explicit type, which is only known after resolving the module.
+	untyped bool
+}
+
+// compared returns the part of the declaration, which matters for compatibility.
+func (d decl) compared() string {
+	if d.sig != "" {
+		return d.sig
+	}
+	return d.src
+}
+
+// relPath returns the import path relative to the first module, which contains it, e.g. /store. Thus, the
+// packages of different versions of a module are compared, even if its path changed between them.
+func relPath(path api.ImportPath, modules ...*api.Module) string {
+	for _, m := range modules {
+		if path == m.Name {
+			return ""
+		}
+		if rel, ok := strings.CutPrefix(path, m.Name+"/"); ok {
+			return "/" + rel
+		}
+	}
+	return path
+}
+
+// declarations returns the Go source of each exported declaration of the module. It does not require resolved
+// type information, thus it works for unresolvable versions as well.
+func declarations(m *api.Module) map[declKey]decl {
+	res := map[declKey]decl{}
+	visit(m, func(key declKey, d decl, _ *string, _ *api.Deprecation) {
+		res[key] = d
+	})
+	return res
+}
+
+// visit calls fn for each exported declaration of the module. Besides packages, each declaration has a Since
+// field, which is passed as well as its deprecation, if any.
+func visit(m *api.Module, fn func(key declKey, d decl, since *string, deprecated *api.Deprecation)) {
+	for path, p := range m.Packages {
+		rel := relPath(path, m)
+		add := func(kind api.SymbolKind, name, src string, since *string, deprecated *api.Deprecation) {
+			fn(declKey{path: rel, name: name}, decl{kind: kind, pkg: path, src: src}, since, deprecated)
+		}
+
+		add(SymbolPackage, "", "package "+p.Name, nil, p.Deprecated)
+
+		for _, block := range p.Consts {
+			for i := range block.Content {
+				c := &block.Content[i]
+				src := "const " + c.RefId.Identifier
+				if c.Expr != "" {
+					src += " = " + c.Expr
+				}
+				add(api.SymbolConst, c.RefId.Identifier, src, &c.Since, c.Deprecated)
+			}
+		}
+
+		for name, v := range p.Vars {
+			d := decl{kind: api.SymbolVar, pkg: path, src: strings.TrimSpace("var " + name + " " + v.TypeDesc.SrcTypeDefinition)}
+			d.untyped = v.TypeDesc.SrcTypeDefinition == ""
+			fn(declKey{path: rel, name: name}, d, &v.Since, v.Deprecated)
+		}
+
+		function := func(name, src, signature string, since *string, deprecated *api.Deprecation) {
+			d := decl{kind: api.SymbolFunc, pkg: path, src: src, sig: signatureTypes(signature)}
+			fn(declKey{path: rel, name: name}, d, since, deprecated)
+		}
+
+		for name, f := range p.Functions {
+			function(name, "func "+f.Signature, f.Signature, &f.Since, f.Deprecated)
+		}
+
+		for _, s := range p.Structs {
+			d := decl{kind: api.SymbolType, pkg: path, src: typeDecl(s), sealed: s.Sealed}
+			fn(declKey{path: rel, name: s.Name}, d, &s.Since, s.Deprecated)
+			for _, f := range s.Constructors {
+				function(f.Name, "func "+f.Signature, f.Signature, &f.Since, f.Deprecated)
+			}
+			for _, method := range s.Methods {
+				key := declKey{path: rel, name: s.Name + "." + method.Name}
+				src := methodDecl(s, method)
+				d := decl{kind: api.SymbolMethod, pkg: path, src: src, iface: s.Interface(), sealed: s.Sealed}
+				if sig := signatureTypes(method.Signature); sig != "" {
+					d.sig = strings.TrimSuffix(src, method.Signature) + sig
+				}
+				fn(key, d, &method.Since, method.Deprecated)
+			}
+			for _, f := range s.Fields {
+				name := f.Name
+				if name == "" {
+					// embedded fields are named by their type
+					name = strings.TrimPrefix(f.TypeDesc.SrcTypeDefinition, "*")
+				}
+				add(api.SymbolField, s.Name+"."+name, strings.TrimSpace(f.Name+" "+f.TypeDesc.SrcTypeDefinition), &f.Since, f.Deprecated)
+			}
+		}
+	}
+}
+
+func typeDecl(s *api.Struct) string {
+	src := "type " + s.Name
+	if len(s.Generics) > 0 {
+		var params []string
+		for _, g := range s.Generics {
+			params = append(params, g.Name+" "+g.TypeDesc.SrcTypeDefinition)
+		}
+		src += "[" + strings.Join(params, ", ") + "]"
+	}
+
+	switch {
+	case s.Interface():
+		src += " interface"
+	case len(s.Stereotypes) > 0:
+		src += " struct"
+	}
+
+	return src
+}
+
+func methodDecl(s *api.Struct, m *api.Method) string {
+	if s.Interface() || m.Recv == nil {
+		return "func (" + s.Name + ") " + m.Signature
+	}
+	return "func (" + m.Recv.TypeString + ") " + m.Signature
+}
+
+// signatureTypes returns the signature like Run(ctx context.Context, n int) error with the parameter and result
+// names removed like Run(context.Context, int) error, because renaming them does not break any client. It returns an
+// empty string, if the signature cannot be parsed.
+func signatureTypes(signature string) string {
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package p\nfunc "+signature, 0)
+	if err != nil || len(file.Decls) != 1 {
+		return ""
+	}
+
+	fn, ok := file.Decls[0].(*ast.FuncDecl)
+	if !ok {
+		return ""
+	}
+
+	fields := func(list *ast.FieldList) []string {
+		var res []string
+		if list == nil {
+			return res
+		}
+		for _, f := range list.List {
+			t := types.ExprString(f.Type)
+			for i := 0; i < len(f.Names) || i == 0; i++ {
+				res = append(res, t)
+			}
+		}
+		return res
+	}
+
+	res := fn.Name.Name
+	if fn.Type.TypeParams != nil {
+		var params []string
+		for _, f := range fn.Type.TypeParams.List {
+			// type parameters are referenced by name in the signature, thus their names matter
+			var names []string
+			for _, name := range f.Names {
+				names = append(names, name.Name)
+			}
+			params = append(params, strings.Join(names, ", ")+" "+types.ExprString(f.Type))
+		}
+		res += "[" + strings.Join(params, ", ") + "]"
+	}
+	res += "(" + strings.Join(fields(fn.Type.Params), ", ") + ")"
+
+	switch results := fields(fn.Type.Results); len(results) {
+	case 0:
+	case 1:
+		res += " " + results[0]
+	default:
+		res += " (" + strings.Join(results, ", ") + ")"
+	}
+
+	return res
+}
@@ -0,0 +1,48 @@
+package apidiff
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+)
+
+func ExampleCompare() {
+	old, err := golang.Parse("testdata/old")
+	if err != nil {
+		panic(err)
+	}
+
+	node, err := golang.Parse("testdata/new")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, c := range Compare(old, node) {
+		fmt.Printf("%s %s: %q -> %q", c.Kind, c.Name, c.Old, c.New)
+		if c.Breaking {
+			fmt.Printf(", %s", c.Reason)
+		}
+		fmt.Println()
+	}
+	// Output:
+	// removed Max: "const Max = 1" -> "", const removed
+	// added Min: "" -> "const Min = 0"
+	// added Sealed.Name: "" -> "func (Sealed) Name() string"
+	// added Store.Put: "" -> "func (Store) Put(key, value string)", method added to interface
+	// changed Sum: "func Sum(values []int) int" -> "func Sum(values []float64) float64", signature changed
+}
+
+func ExampleNext() {
+	fmt.Println(Next("v1.4.2", Major), Next("v1.4.2", Minor), Next("v1.4.2", Patch), Next("v0.3.1", Major))
+	fmt.Println(Allowed("v1.4.2", "v1.5.0", Major), Allowed("v0.3.1", "v0.4.0", Major))
+	// Output:
+	// v2.0.0 v1.5.0 v1.4.3 v0.4.0
+	// false true
+}
+
+func Example_relPath() {
+	m := &api.Module{Name: "example.com/m"}
+	fmt.Printf("%q %q %q\n", relPath("example.com/m", m), relPath("example.com/m/store", m), relPath("example.com/mx/store", m))
+	// Output:
+	// "" "/store" "example.com/mx/store"
+}
@@ -0,0 +1,109 @@
+package apidiff
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"golang.org/x/mod/semver"
+	"strconv"
+	"strings"
+)
+
+// Bump is the semantic version increment, which is required by a set of changes.
+type Bump int
+
+const (
+	Patch Bump = iota // nothing exported has changed
+	Minor             // only compatible additions
+	Major             // at least one breaking change
+)
+
+func (b Bump) String() string {
+	switch b {
+	case Major:
+		return "major"
+	case Minor:
+		return "minor"
+	default:
+		return "patch"
+	}
+}
+
+// classify decides, whether the change may break existing clients. Any removal or change of a declaration breaks
+// clients, but additions only break the implementations of an interface. An interface with unexported methods
+// cannot be implemented by clients, thus adding methods to it is compatible.
+func classify(c Change, d decl) Change {
+	switch c.Kind {
+	case Removed:
+		c.Breaking, c.Reason = true, string(c.Symbol)+" removed"
+	case Changed:
+		c.Breaking = true
+		switch c.Symbol {
+		case api.SymbolConst:
+			c.Reason = "value changed"
+		case api.SymbolField, api.SymbolVar:
+			c.Reason = "type changed"
+		case api.SymbolFunc, api.SymbolMethod:
+			c.Reason = "signature changed"
+		case SymbolPackage:
+			c.Reason = "package renamed"
+		default:
+			c.Reason = "declaration changed"
+		}
+	case Added:
+		if c.Symbol == api.SymbolMethod && d.iface && !d.sealed {
+			c.Breaking, c.Reason = true, "method added to interface"
+		}
+	}
+
+	return c
+}
+
+// Required returns the version increment, which is required by the given changes.
+func Required(changes []Change) Bump {
+	res := Patch
+	for _, c := range changes {
+		if c.Breaking {
+			return Major
+		}
+		res = Minor
+	}
+	return res
+}
+
+// Next returns the smallest version after the release last, which satisfies the bump. Before v1, breaking changes only require a
+// minor increment. Without a last version, the result is v0.1.0.
+func Next(last string, bump Bump) string {
+	if !semver.IsValid(last) {
+		return "v0.1.0"
+	}
+
+	major, minor, patch := versionParts(last)
+	switch {
+	case bump == Major && major > 0:
+		return fmt.Sprintf("v%d.0.0", major+1)
+	case bump == Major || bump == Minor:
+		return fmt.Sprintf("v%d.%d.0", major, minor+1)
+	default:
+		return fmt.Sprintf("v%d.%d.%d", major, minor, patch+1)
+	}
+}
+
+// Allowed returns true, if releasing next after last satisfies the bump. An unknown last version allows anything.
+func Allowed(last, next string, bump Bump) bool {
+	if !semver.IsValid(last) {
+		return true
+	}
+	if !semver.IsValid(next) {
+		return false
+	}
+
+	return semver.Compare(semver.Canonical(next), Next(last, bump)) >= 0
+}
+
+func versionParts(v string) (major, minor, patch int) {
+	parts := strings.SplitN(strings.TrimPrefix(semver.Canonical(v), "v"), ".", 3)
+	major, _ = strconv.Atoi(parts[0])
+	minor, _ = strconv.Atoi(parts[1])
+	patch, _ = strconv.Atoi(strings.FieldsFunc(parts[2], func(r rune) bool { return r == '-' || r == '+' })[0])
+	return
+}
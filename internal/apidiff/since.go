@@ -0,0 +1,37 @@
+package apidiff
+
+import "github.com/worldiety/gdoc/internal/api"
+
+// Release is a parsed version of a module, usually of a git tag.
+type Release struct {
+	Version string
+	Module  *api.Module
+}
+
+// AddSince sets the Since field of each exported declaration of the module to the first release, which already
+// declares it. The releases must be sorted from oldest to newest. Declarations, which have not been released yet,
+// keep an empty Since. Likewise, the Since field of each deprecation is set to the first release, which deprecates
+// the declaration.
+func AddSince(m *api.Module, releases []Release) {
+	first := map[declKey]string{}
+	deprecated := map[declKey]string{}
+	for _, r := range releases {
+		visit(r.Module, func(key declKey, _ decl, _ *string, d *api.Deprecation) {
+			if _, ok := first[key]; !ok {
+				first[key] = r.Version
+			}
+			if _, ok := deprecated[key]; !ok && d != nil {
+				deprecated[key] = r.Version
+			}
+		})
+	}
+
+	visit(m, func(key declKey, _ decl, since *string, d *api.Deprecation) {
+		if since != nil {
+			*since = first[key]
+		}
+		if d != nil {
+			d.Since = deprecated[key]
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package apidiff
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+)
+
+func ExampleAddSince() {
+	old, err := golang.Parse("testdata/old")
+	if err != nil {
+		panic(err)
+	}
+
+	next, err := golang.Parse("testdata/new")
+	if err != nil {
+		panic(err)
+	}
+
+	node, err := golang.Parse("testdata/new")
+	if err != nil {
+		panic(err)
+	}
+
+	// the working tree adds nothing on top of the last release, thus nothing is unreleased
+	AddSince(node, []Release{{Version: "v1.0.0", Module: old}, {Version: "v1.1.0", Module: next}})
+
+	p := node.Packages["example.com/m/a"]
+	fmt.Println("Min", p.Consts[0].Content[0].Since)
+	fmt.Println("Default", p.Vars["Default"].Since)
+	fmt.Println("Run", p.Functions["Run"].Since)
+	for _, name := range []string{"Sealed", "Store"} {
+		s := p.Structs[name]
+		fmt.Println(s.Name, s.Since)
+		for _, m := range s.Methods {
+			fmt.Println(s.Name+"."+m.Name, m.Since)
+		}
+	}
+
+	// a declaration, which is not part of any release, keeps an empty Since
+	AddSince(node, []Release{{Version: "v1.0.0", Module: old}})
+	fmt.Printf("Min %q\n", p.Consts[0].Content[0].Since)
+	// Output:
+	// Min v1.1.0
+	// Default v1.0.0
+	// Run v1.0.0
+	// Sealed v1.0.0
+	// Sealed.Name v1.1.0
+	// Store v1.0.0
+	// Store.Get v1.0.0
+	// Store.Put v1.1.0
+	// Min ""
+}
@@ -0,0 +1,30 @@
+// Package a is the next version of the API.
+package a
+
+import "strings"
+
+const Min = 0
+
+// Default has an inferred type, which is unknown without resolving the module.
+var Default *strings.Replacer = strings.NewReplacer()
+
+// Run is compatible to the previous version, because only the parameter names change.
+func Run(key string, count int) (bool, error) {
+	return false, nil
+}
+
+// Sum changes the type of its parameter.
+func Sum(values []float64) float64 {
+	return 0
+}
+
+type Store interface {
+	Get(key string) string
+	Put(key, value string)
+}
+
+// Sealed cannot be implemented by other packages.
+type Sealed interface {
+	Name() string
+	seal()
+}
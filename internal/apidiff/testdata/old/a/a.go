@@ -0,0 +1,28 @@
+// Package a is the previous version of the API.
+package a
+
+import "strings"
+
+const Max = 1
+
+// Default has an inferred type, which is unknown without resolving the module.
+var Default = strings.NewReplacer()
+
+// Run is compatible to the next version, because only the parameter names change.
+func Run(name string, n int) (ok bool, err error) {
+	return false, nil
+}
+
+// Sum changes the type of its parameter.
+func Sum(values []int) int {
+	return 0
+}
+
+type Store interface {
+	Get(id string) string
+}
+
+// Sealed cannot be implemented by other packages.
+type Sealed interface {
+	seal()
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+)
+
+const (
+	// HashAnchors are the stable but unreadable anchors like gd4f1c…, see api.HashAnchors.
+	HashAnchors = "hash"
+	// ReadableAnchors are derived from the import path and the identifier, see api.ReadableAnchors.
+	ReadableAnchors = "readable"
+
+	// anchorsFile maps the hash anchors to the configured ones, so that published links keep working.
+	anchorsFile = "anchors.json"
+)
+
+// anchors returns the configured anchor scheme for the given modules, which are usually the versions of the same
+// module in ascending order.
+func anchors(cfg Config, nodes ...*api.Module) (api.AnchorScheme, error) {
+	switch cfg.Anchors {
+	case HashAnchors, "":
+		return api.HashAnchors{}, nil
+	case ReadableAnchors:
+		return api.NewAnchors(api.ReadableAnchors{Module: nodes[len(nodes)-1].Name}, nodes...), nil
+	default:
+		return nil, fmt.Errorf("invalid anchor scheme: %s", cfg.Anchors)
+	}
+}
+
+// link applies the configured anchor scheme to the modules and replaces the doc links of their comments by cross
+// references. The modules share the same scheme, so that the versions of a module can link each other.
+func link(cfg Config, nodes ...*api.Module) error {
+	scheme, err := anchors(cfg, nodes...)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		node.Anchors = scheme
+		golang.LinkComments(node)
+	}
+
+	return nil
+}
+
+// anchorFiles returns the mapping of the hash anchors of the linked module, unless they are used anyway.
+func anchorFiles(node *api.Module) (map[string][]byte, error) {
+	a, ok := node.Anchors.(*api.Anchors)
+	if !ok {
+		return nil, nil
+	}
+
+	buf, err := json.Marshal(a.Legacy())
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal anchors: %w", err)
+	}
+
+	return map[string][]byte{anchorsFile: buf}, nil
+}
@@ -1,14 +1,19 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"github.com/worldiety/gdoc/internal/api"
 	"github.com/worldiety/gdoc/internal/generator/asciidoc"
+	"github.com/worldiety/gdoc/internal/generator/html"
+	"github.com/worldiety/gdoc/internal/generator/search"
 	"github.com/worldiety/gdoc/internal/parser/golang"
 	"gopkg.in/yaml.v3"
 	"log"
+	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -20,6 +25,8 @@ func (f OutputFormat) Category() string {
 		return "stdlib"
 	case Adoc, Pdf:
 		return "adoc"
+	case Native:
+		return "html"
 	default:
 		return ""
 	}
@@ -31,13 +38,33 @@ const (
 	Adoc = "adoc"
 	Pdf  = "pdf"
 	Html = "html"
+	// Native is a self-contained html page, which does not require asciidoctor.
+	Native = "native"
 )
 
 type Config struct {
-	ModPath      string
-	OutputFormat string
-	Packages     string
-	PkgSep       string
+	ModPath       string
+	OutputFormat  string
+	Packages      string
+	PkgSep        string
+	Diagrams      string
+	GraphCollapse string
+	GraphStdlib   bool
+	GraphExternal bool
+	GraphCycles   bool
+	CallDepth     int
+	CallGraph     string
+	Since         bool
+	CacheDir      string
+	Versions      string
+	Out           string
+	Strict        bool
+	Diagnostics   string
+	Source        string
+	SourceRepo    string
+	Bodies        string
+	Anchors       string
+	Templates     string
 }
 
 func (c *Config) Reset() {
@@ -49,19 +76,92 @@ func (c *Config) Reset() {
 	c.ModPath = wd
 	c.OutputFormat = Adoc
 	c.PkgSep = "/"
+	c.GraphCycles = true
+	c.CallGraph = golang.CallGraphCHA
+	c.Out = "out"
+	c.Anchors = HashAnchors
+	if dir, err := os.UserCacheDir(); err == nil {
+		c.CacheDir = filepath.Join(dir, "gdoc")
+	}
 }
 
 func (c *Config) Flags(flags *flag.FlagSet) {
 	flags.StringVar(&c.ModPath, "modPath", c.ModPath, "the modules path to use")
 	flags.StringVar(&c.OutputFormat, "format", c.OutputFormat, "default is adoc. yaml|json are available as well. "+
-		"html is available, if ascidoctor is installed. "+
+		"html is available, if ascidoctor is installed. native is a self-contained html page. "+
 		"pdf is available, if asciidoctor-pdf is installed")
 	flags.StringVar(&c.Packages, "packages", c.Packages, "if not empty, only scan the listed packages separated by ;")
 	flags.StringVar(&c.PkgSep, "pkgSep", c.PkgSep, "sets the path separator between packages. Default is / which is not json-pointer friendly")
+	flags.StringVar(&c.Diagrams, "diagrams", c.Diagrams, "if not empty, embed diagrams as asciidoctor-diagram blocks and write them as standalone files. "+
+		"dot|plantuml|mermaid require asciidoctor-diagram, svg is rendered built-in")
+	flags.StringVar(&c.GraphCollapse, "graphCollapse", c.GraphCollapse, "collapse all packages below the listed import path prefixes separated by ; into a single node")
+	flags.BoolVar(&c.GraphStdlib, "graphStdlib", c.GraphStdlib, "include standard library packages in the import graph")
+	flags.BoolVar(&c.GraphExternal, "graphExternal", c.GraphExternal, "include packages of other modules in the import graph")
+	flags.BoolVar(&c.GraphCycles, "graphCycles", c.GraphCycles, "highlight import cycles")
+	flags.IntVar(&c.CallDepth, "callDepth", c.CallDepth, "if greater than 0, document the calls between exported functions up to the given depth")
+	flags.StringVar(&c.CallGraph, "callGraph", c.CallGraph, "the call graph algorithm, either cha or vta")
+	flags.BoolVar(&c.Since, "since", c.Since, "annotate each symbol with the first semver tag, which declares it. Requires git")
+	flags.StringVar(&c.CacheDir, "cache", c.CacheDir, "the directory to cache parsed tags in. If empty, nothing is cached")
+	flags.StringVar(&c.Versions, "versions", c.Versions, "if not empty, generate the native html documentation for each listed git tag or branch separated by ;, "+
+		"ordered from oldest to newest")
+	flags.StringVar(&c.Out, "out", c.Out, "the output directory of the versioned documentation")
+	flags.BoolVar(&c.Strict, "strict", c.Strict, "fail on unresolved comment links, missing anchors and unknown types instead of printing warnings")
+	flags.StringVar(&c.Diagnostics, "diagnostics", c.Diagnostics, "if not empty, write the unresolved references as json report to this file")
+	flags.StringVar(&c.Source, "source", c.Source, "if not empty, link each declaration to its source. github|gitlab|gitea|bitbucket|file or a url template "+
+		"with the placeholders {repo}, {commit}, {root}, {path}, {line} and {endLine}")
+	flags.StringVar(&c.SourceRepo, "sourceRepo", c.SourceRepo, "the web url of the repository for -source. Default is derived from the origin remote")
+	flags.StringVar(&c.Bodies, "bodies", c.Bodies, "if not empty, embed the source of the functions and methods, which match one of the listed import path prefixes "+
+		"or stereotypes separated by ;, like func, constructor, method or the stereotype of the receiver. * matches all")
+	flags.StringVar(&c.Anchors, "anchors", c.Anchors, "the anchors of the declarations. hash|readable, where readable anchors look like internal-api--Symbol--Name "+
+		"and require the anchors.json file, which maps the hash anchors, to redirect published links")
+	flags.StringVar(&c.Templates, "templates", c.Templates, "if not empty, the *.tmpl files of this directory override or extend the embedded asciidoc templates "+
+		"of the same name, like package, struct or appendix")
 }
 
 // Apply takes a Config and uses the contained instructions to generate documentation.
 func Apply(cfg Config) ([]byte, error) {
+	node, err := Load(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return Render(cfg, node)
+}
+
+// Load parses and resolves the configured module and logs its diagnostics.
+func Load(cfg Config) (*api.Module, error) {
+	node, diagnostics, err := LoadContext(context.Background(), cfg)
+	warn(cfg, diagnostics)
+	return node, err
+}
+
+// LoadContext is like Load, but returns the diagnostics instead of logging them and stops with the error of the
+// context, as soon as it is done. The context is checked between the parsing and the resolving steps, which are not
+// interrupted themselves. In strict mode, the diagnostics are returned together with the error.
+func LoadContext(ctx context.Context, cfg Config) (*api.Module, []golang.Diagnostic, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	node, err := load(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := link(cfg, node); err != nil {
+		return nil, nil, err
+	}
+
+	diagnostics, err := diagnose(cfg, node)
+	if err != nil {
+		return nil, diagnostics, err
+	}
+
+	return node, diagnostics, nil
+}
+
+// load parses and resolves the configured module, but neither links its comments nor diagnoses it.
+func load(ctx context.Context, cfg Config) (*api.Module, error) {
 	pkgs := strings.Split(cfg.Packages, ";")
 	if len(pkgs) == 1 && pkgs[0] == "" {
 		pkgs = nil
@@ -71,32 +171,70 @@ func Apply(cfg Config) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("cannot parse from %s: %w", cfg.ModPath, err)
 	}
-	// add information not available in the ast package to the module
-	err = golang.Resolve(node)
-	if err != nil {
-		return nil, fmt.Errorf("cannot resolve %s: %w", node.Name, err)
+
+	if err := resolve(ctx, cfg, node); err != nil {
+		return nil, err
 	}
 
-	tmp := map[api.ImportPath]*api.Package{}
-	if cfg.PkgSep != "/" {
-		for path, p := range node.Packages {
-			qualifier := strings.ReplaceAll(path, "/", cfg.PkgSep)
-			tmp[qualifier] = p
+	return node, nil
+}
 
-			var impTmp []api.Import
-			for _, s := range p.Imports {
-				qualifier := strings.ReplaceAll(string(s), "/", cfg.PkgSep)
-				impTmp = append(impTmp, api.Import(qualifier))
-			}
-			p.Imports = impTmp
+// resolve adds the information, which is not available in the ast package, to an already parsed module. The
+// comments are linked afterwards, see link, because the anchor scheme depends on the resolved declarations.
+func resolve(ctx context.Context, cfg Config, node *api.Module) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := golang.Resolve(cfg.ModPath, node); err != nil {
+		return fmt.Errorf("cannot resolve %s: %w", node.Name, err)
+	}
+
+	return annotate(ctx, cfg, node)
+}
+
+// annotate adds the configured sources, release tags and calls to a module with resolved types.
+func annotate(ctx context.Context, cfg Config, node *api.Module) error {
+	if cfg.Bodies != "" {
+		if err := addBodies(cfg, node); err != nil {
+			return fmt.Errorf("cannot embed the sources of %s: %w", node.Name, err)
+		}
+	}
+
+	if cfg.Source != "" {
+		if err := addSourceURLs(cfg, node); err != nil {
+			return fmt.Errorf("cannot link the sources of %s: %w", node.Name, err)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if cfg.Since {
+		if err := addSince(cfg, node); err != nil {
+			return fmt.Errorf("cannot determine versions of %s: %w", node.Name, err)
 		}
+	}
 
-		node.Packages = tmp
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
+	if cfg.CallDepth > 0 {
+		if err := golang.ResolveCalls(cfg.ModPath, node, cfg.CallDepth, cfg.CallGraph); err != nil {
+			return fmt.Errorf("cannot resolve calls of %s: %w", node.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Render generates the documentation of an already loaded module in the configured output format.
+func Render(cfg Config, node *api.Module) ([]byte, error) {
 	switch cfg.OutputFormat {
 	case Json:
-		buf, err := json.Marshal(node)
+		buf, err := json.Marshal(withPkgSep(node, cfg.PkgSep))
 		if err != nil {
 			return nil, fmt.Errorf("cannot marshal json: %w", err)
 		}
@@ -104,17 +242,119 @@ func Apply(cfg Config) ([]byte, error) {
 		return buf, nil
 
 	case Yaml:
-		buf, err := yaml.Marshal(node)
+		buf, err := yaml.Marshal(withPkgSep(node, cfg.PkgSep))
 		if err != nil {
 			return nil, fmt.Errorf("cannot marshal yaml: %w", err)
 		}
 
 		return buf, nil
 	case Adoc, Pdf, Html:
-		output, _ := asciidoc.CreateModuleTemplate(golang.NewAModule(*node))
+		amod := golang.NewAModule(*node)
+		diagrams, err := asciidocDiagrams(cfg, moduleDiagrams(cfg, node), 2)
+		if err != nil {
+			return nil, err
+		}
+		amod.Diagrams = diagrams
+		amod.Wiring = wiringOrder(node)
+
+		for path, p := range amod.Packages {
+			diagrams, err := asciidocDiagrams(cfg, packageDiagrams(node, node.Packages[path]), 3)
+			if err != nil {
+				return nil, err
+			}
+			p.Diagrams = diagrams
+			amod.Packages[path] = p
+		}
+
+		tpl, err := asciidoc.NewTemplates(cfg.Templates)
+		if err != nil {
+			return nil, err
+		}
+
+		output, err := asciidoc.CreateModuleTemplate(tpl, amod)
+		if err != nil {
+			return nil, err
+		}
 
 		return output.Bytes(), nil
+	case Native:
+		return renderNative(cfg, node, html.Options{})
 	default:
 		return nil, fmt.Errorf("invalid output format: %s", cfg.OutputFormat)
 	}
 }
+
+// renderNative generates a self-contained html page, which embeds svg diagrams.
+func renderNative(cfg Config, node *api.Module, opts html.Options) ([]byte, error) {
+	diagrams, err := htmlDiagrams(cfg, node)
+	if err != nil {
+		return nil, err
+	}
+	opts.Diagrams = diagrams
+
+	return html.Render(node, opts)
+}
+
+// Files returns additional standalone files, which belong next to the rendered documentation.
+func Files(cfg Config, node *api.Module) (map[string][]byte, error) {
+	res, err := diagramFiles(cfg, node)
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		res = map[string][]byte{}
+	}
+
+	if cfg.OutputFormat == Native || cfg.OutputFormat == Html {
+		files, err := search.Files(node)
+		if err != nil {
+			return nil, err
+		}
+
+		for name, buf := range files {
+			res[name] = buf
+		}
+	}
+
+	if cfg.OutputFormat == Html {
+		res[search.DocinfoFile] = search.Docinfo
+	}
+
+	if cfg.OutputFormat == Native || cfg.OutputFormat == Html || cfg.OutputFormat == Adoc {
+		files, err := anchorFiles(node)
+		if err != nil {
+			return nil, err
+		}
+
+		for name, buf := range files {
+			res[name] = buf
+		}
+	}
+
+	return res, nil
+}
+
+// withPkgSep returns a shallow copy of the module, whose import paths use the given separator.
+func withPkgSep(node *api.Module, sep string) *api.Module {
+	if sep == "/" {
+		return node
+	}
+
+	res := *node
+	res.Packages = map[api.ImportPath]*api.Package{}
+	for path, p := range node.Packages {
+		qualifier := strings.ReplaceAll(path, "/", sep)
+		tmp := *p
+
+		var impTmp []api.Import
+		for _, s := range p.Imports {
+			qualifier := strings.ReplaceAll(string(s), "/", sep)
+			impTmp = append(impTmp, api.Import(qualifier))
+		}
+		tmp.Imports = impTmp
+		res.Packages[qualifier] = &tmp
+	}
+
+	return &res
+}
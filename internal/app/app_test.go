@@ -0,0 +1,50 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/fixture"
+	"regexp"
+)
+
+func ExampleLoadContext() {
+	cfg := Config{ModPath: fixture.Dir(), OutputFormat: Adoc, Anchors: ReadableAnchors, PkgSep: "/"}
+	node, _, err := LoadContext(context.Background(), cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	doc, err := Render(cfg, node)
+	if err != nil {
+		panic(err)
+	}
+
+	files, err := Files(cfg, node)
+	if err != nil {
+		panic(err)
+	}
+
+	var legacy map[string]string
+	if err := json.Unmarshal(files[anchorsFile], &legacy); err != nil {
+		panic(err)
+	}
+
+	// the anchors of the document, of the comment links and of anchors.json come from the same scheme
+	for _, ref := range []api.RefId{
+		api.NewRefID(fixture.Name+"/store-index", "Index"),
+		api.NewRefID(fixture.Name+"/store/index", "Index"),
+		api.NewRefID(fixture.Name+"/store", "Mode"),
+	} {
+		anchor := node.Anchor(ref)
+		fmt.Println(anchor, legacy[ref.LegacyID()] == anchor, bytes.Contains(doc, []byte("[["+anchor+"]]")))
+	}
+	fmt.Println(bytes.Contains(doc, []byte("<<store--Mode, Mode>>")), regexp.MustCompile(`gd[0-9a-f]{56}`).Match(doc))
+	// Output:
+	// store-index--Index true true
+	// store-index--Index-0d8426c1 true true
+	// store--Mode true true
+	// true false
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// funcStereotype matches the functions of a package, which are neither constructors nor methods, see Config.Bodies.
+const funcStereotype = "func"
+
+// addBodies sets the body of each function and method, which matches one of the configured import path prefixes
+// or stereotypes. The body is cut from the source file at the recorded position, so its lines match the file.
+func addBodies(cfg Config, node *api.Module) error {
+	modRoot, err := golang.ModRoot(cfg.ModPath)
+	if err != nil {
+		return err
+	}
+
+	filters := strings.Split(cfg.Bodies, ";")
+	match := func(fn *api.Function, stereotypes ...api.Stereotype) bool {
+		for _, f := range filters {
+			f = strings.TrimSpace(f)
+			if f == "" {
+				continue
+			}
+
+			// import paths match by whole segments, so that a/b does not match a/bc
+			path, prefix := fn.TypeDefinition.ImportPath, strings.TrimSuffix(f, "/")
+			if f == "*" || path == prefix || strings.HasPrefix(path, prefix+"/") {
+				return true
+			}
+
+			for _, st := range stereotypes {
+				if string(st) == f {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	files := map[string][]string{}
+	body := func(fn *api.Function) error {
+		p := fn.Position
+		if !p.Valid() {
+			return nil
+		}
+
+		lines, ok := files[p.File]
+		if !ok {
+			buf, err := os.ReadFile(filepath.Join(modRoot, filepath.FromSlash(p.File)))
+			if err != nil {
+				return fmt.Errorf("cannot read source of %s: %w", fn.Name, err)
+			}
+
+			lines = strings.Split(string(buf), "\n")
+			files[p.File] = lines
+		}
+
+		if p.Line > p.EndLine || p.EndLine > len(lines) {
+			return fmt.Errorf("position %s of %s is out of range", p, fn.Name)
+		}
+
+		src := strings.Join(lines[p.Line-1:p.EndLine], "\n")
+		if hasBody(src) {
+			fn.Body = src
+		}
+		return nil
+	}
+
+	for _, p := range node.Packages {
+		for _, fn := range p.Functions {
+			if match(fn, funcStereotype) {
+				if err := body(fn); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, s := range p.Structs {
+			for _, fn := range s.Constructors {
+				if match(fn, append([]api.Stereotype{api.StereotypeConstructor}, s.Stereotypes...)...) {
+					if err := body(fn); err != nil {
+						return err
+					}
+				}
+			}
+
+			if s.Interface() {
+				// interface methods have no body
+				continue
+			}
+
+			for _, m := range s.Methods {
+				if match(m.Function, append([]api.Stereotype{api.StereotypeMethod}, s.Stereotypes...)...) {
+					if err := body(m.Function); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// hasBody returns false, if the source is a function declaration without body, like one implemented in assembly.
+func hasBody(src string) bool {
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package p\n"+src, 0)
+	if err != nil || len(file.Decls) == 0 {
+		return true
+	}
+
+	fn, ok := file.Decls[0].(*ast.FuncDecl)
+	return !ok || fn.Body != nil
+}
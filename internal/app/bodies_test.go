@@ -0,0 +1,56 @@
+package app
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/fixture"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+	"os"
+	"path/filepath"
+)
+
+func Example_addBodies() {
+	dir, err := os.MkdirTemp("", "bodies")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := fixture.Copy(dir); err != nil {
+		panic(err)
+	}
+
+	// Sum is implemented in assembly and has no body
+	src := `package store
+
+// Sum adds both values.
+func Sum(a, b int) int
+`
+	if err := os.WriteFile(filepath.Join(dir, "store", "sum.go"), []byte(src), 0644); err != nil {
+		panic(err)
+	}
+
+	node, err := golang.Parse(dir)
+	if err != nil {
+		panic(err)
+	}
+
+	if err := golang.Resolve(dir, node); err != nil {
+		panic(err)
+	}
+
+	// the filter matches store, but not store-index
+	if err := addBodies(Config{ModPath: dir, Bodies: "example.com/m/store/"}, node); err != nil {
+		panic(err)
+	}
+
+	store := node.Packages[fixture.Name+"/store"]
+	fmt.Println(store.Structs["Store"].Constructors[0].Body)
+	fmt.Printf("%q\n", store.Functions["Sum"].Body)
+	fmt.Printf("%q\n", node.Packages[fixture.Name+"/store-index"].Functions["Index"].Body)
+	// Output:
+	// func NewStore(mode Mode) *Store {
+	// 	return &Store{Mode: mode}
+	// }
+	// ""
+	// ""
+}
@@ -0,0 +1,181 @@
+package app
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/apidiff"
+	"github.com/worldiety/gdoc/internal/git"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+	"golang.org/x/mod/semver"
+	"log"
+	"os"
+	"strings"
+)
+
+const (
+	Text        = "text"
+	workingTree = "working tree"
+)
+
+// CompatConfig configures the compatibility check of the module against a previous version, see Compat.
+type CompatConfig struct {
+	ModPath      string
+	OutputFormat string
+	Baseline     string // a json snapshot of the previous API, as written by -format json
+	Base         string // the git ref of the previous version, defaults to the last semver tag
+	Version      string // the version to be released, defaults to the next minor version
+}
+
+func (c *CompatConfig) Reset() {
+	wd, err := golang.ModWdRoot()
+	if err != nil {
+		log.Fatal(fmt.Errorf("could not walk to mod root directory: %w", err))
+	}
+
+	c.ModPath = wd
+	c.OutputFormat = Text
+}
+
+func (c *CompatConfig) Flags(flags *flag.FlagSet) {
+	flags.StringVar(&c.ModPath, "modPath", c.ModPath, "the modules path to use")
+	flags.StringVar(&c.OutputFormat, "format", c.OutputFormat, "default is text. json is available as well")
+	flags.StringVar(&c.Baseline, "baseline", c.Baseline, "if not empty, compare against this json snapshot of the previous API instead of a git ref")
+	flags.StringVar(&c.Base, "base", c.Base, "the git ref of the previous version. Default is the last semver tag")
+	flags.StringVar(&c.Version, "version", c.Version, "the version to be released. Default is the next minor version after the last tag")
+}
+
+// CompatReport is the verdict of Compat.
+type CompatReport struct {
+	apidiff.Report
+	Last      string // the last released version or empty
+	Required  string // the required semver bump, see apidiff.Bump
+	Suggested string // the smallest version, which satisfies the required bump
+	Version   string // the version to be released
+	Allowed   bool   // false, if the changes require a larger bump than Version provides
+}
+
+// Compat compares the resolved working tree of the module with the baseline and decides, whether the changes are
+// allowed for the configured version.
+func Compat(cfg CompatConfig) (*CompatReport, error) {
+	tags, err := git.Tags(cfg.ModPath)
+	if err != nil && cfg.Baseline == "" {
+		return nil, err
+	}
+
+	// the bump is relative to the last release, thus prereleases like v1.5.0-rc.1 are skipped
+	var last string
+	for i := len(tags) - 1; i >= 0 && last == ""; i-- {
+		if semver.Prerelease(tags[i]) == "" {
+			last = tags[i]
+		}
+	}
+
+	var old *api.Module
+	oldName := cfg.Baseline
+	switch {
+	case cfg.Baseline != "":
+		old, err = loadSnapshot(cfg.Baseline)
+	case cfg.Base != "":
+		oldName = cfg.Base
+		old, err = LoadRef(cfg.ModPath, cfg.Base)
+	case last != "":
+		oldName = last
+		old, err = LoadRef(cfg.ModPath, last)
+	default:
+		return nil, fmt.Errorf("no baseline: there is neither a -baseline, a -base nor a semver tag")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := golang.Parse(cfg.ModPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse from %s: %w", cfg.ModPath, err)
+	}
+
+	// a json baseline is resolved, thus the working tree must be resolved as well to be comparable. A git ref is
+	// only parsed, but apart from inferred variable types, which are skipped, the compared declarations do not
+	// depend on the resolved types
+	if err := golang.Resolve(cfg.ModPath, node); err != nil {
+		return nil, fmt.Errorf("cannot resolve %s: %w", node.Name, err)
+	}
+
+	changes := apidiff.Compare(old, node)
+	bump := apidiff.Required(changes)
+	version := cfg.Version
+	if version == "" {
+		version = apidiff.Next(last, apidiff.Minor)
+	}
+
+	return &CompatReport{
+		Report:    apidiff.Report{Module: node.Name, Old: oldName, New: workingTree, Changes: changes},
+		Last:      last,
+		Required:  bump.String(),
+		Suggested: apidiff.Next(last, bump),
+		Version:   version,
+		Allowed:   apidiff.Allowed(last, version, bump),
+	}, nil
+}
+
+// RenderCompat formats the report in the configured output format.
+func RenderCompat(cfg CompatConfig, r *CompatReport) ([]byte, error) {
+	switch cfg.OutputFormat {
+	case Json:
+		buf, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal json: %w", err)
+		}
+		return buf, nil
+	case Text:
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "API changes of %s from %s to %s:\n", r.Module, r.Old, r.New)
+		for _, c := range r.Changes {
+			verdict := "compatible"
+			if c.Breaking {
+				verdict = "BREAKING"
+			}
+
+			decl := c.New
+			if c.Kind == apidiff.Removed {
+				decl = c.Old
+			}
+
+			fmt.Fprintf(&sb, "  %-10s %-7s %s %s: %s", verdict, c.Kind, c.Package, c.Name, decl)
+			if c.Reason != "" {
+				fmt.Fprintf(&sb, " (%s)", c.Reason)
+			}
+			sb.WriteString("\n")
+		}
+
+		last := r.Last
+		if last == "" {
+			last = "none"
+		}
+		fmt.Fprintf(&sb, "required bump: %s, last version: %s, suggested version: %s\n", r.Required, last, r.Suggested)
+		if r.Allowed {
+			fmt.Fprintf(&sb, "version %s is allowed\n", r.Version)
+		} else {
+			fmt.Fprintf(&sb, "version %s is not allowed, the changes require at least %s\n", r.Version, r.Suggested)
+		}
+		return []byte(sb.String()), nil
+	default:
+		return nil, fmt.Errorf("invalid output format: %s", cfg.OutputFormat)
+	}
+}
+
+// loadSnapshot reads a module, which has been written with -format json.
+func loadSnapshot(name string) (*api.Module, error) {
+	buf, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read snapshot: %w", err)
+	}
+
+	var m api.Module
+	if err := json.Unmarshal(buf, &m); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal snapshot %s: %w", name, err)
+	}
+
+	return &m, nil
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/coverage"
+	"github.com/worldiety/gdoc/internal/generator/badge"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+	"log"
+	"strings"
+	"text/tabwriter"
+)
+
+// CoverageConfig configures the documentation coverage report, see Coverage.
+type CoverageConfig struct {
+	ModPath      string
+	OutputFormat string
+	Packages     string
+	Min          float64 // the required overall coverage in percent
+	Badge        string  // the file name of the svg badge, empty to omit it
+}
+
+func (c *CoverageConfig) Reset() {
+	wd, err := golang.ModWdRoot()
+	if err != nil {
+		log.Fatal(fmt.Errorf("could not walk to mod root directory: %w", err))
+	}
+
+	c.ModPath = wd
+	c.OutputFormat = Text
+	c.Badge = "coverage.svg"
+}
+
+func (c *CoverageConfig) Flags(flags *flag.FlagSet) {
+	flags.StringVar(&c.ModPath, "modPath", c.ModPath, "the modules path to use")
+	flags.StringVar(&c.OutputFormat, "format", c.OutputFormat, "default is text. adoc|json are available as well")
+	flags.StringVar(&c.Packages, "packages", c.Packages, "if not empty, only scan the listed packages separated by ;")
+	flags.Float64Var(&c.Min, "min", c.Min, "the required overall coverage in percent. If it is not reached, gdoc exits with 1")
+	flags.StringVar(&c.Badge, "badge", c.Badge, "the file to write the svg badge to. If empty, no badge is written")
+}
+
+// Coverage parses the configured module and measures its documentation coverage. Types are not resolved, because
+// only the comments matter.
+func Coverage(cfg CoverageConfig) (*coverage.Report, error) {
+	pkgs := strings.Split(cfg.Packages, ";")
+	if len(pkgs) == 1 && pkgs[0] == "" {
+		pkgs = nil
+	}
+
+	node, err := golang.Parse(cfg.ModPath, pkgs...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse from %s: %w", cfg.ModPath, err)
+	}
+
+	r := coverage.Compute(node)
+	return &r, nil
+}
+
+// CoverageBadge renders the overall coverage as svg badge.
+func CoverageBadge(r *coverage.Report) []byte {
+	p := r.Total.Percent()
+	return []byte(badge.Svg("doc coverage", fmt.Sprintf("%.0f%%", p), badge.Percent(p)))
+}
+
+// RenderCoverage formats the report in the configured output format as a table with a row per package and a
+// column per symbol kind.
+func RenderCoverage(cfg CoverageConfig, r *coverage.Report) ([]byte, error) {
+	cell := func(c coverage.Counts) string {
+		if c.Total == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%d/%d %.1f%%", c.Documented, c.Total, c.Percent())
+	}
+
+	row := func(name string, kinds map[api.SymbolKind]coverage.Counts, total coverage.Counts) []string {
+		res := []string{name}
+		for _, kind := range coverage.Kinds() {
+			res = append(res, cell(kinds[kind]))
+		}
+		return append(res, cell(total))
+	}
+
+	header := []string{"package"}
+	for _, kind := range coverage.Kinds() {
+		header = append(header, string(kind))
+	}
+	header = append(header, "total")
+
+	rows := [][]string{header}
+	for _, p := range r.Packages {
+		rows = append(rows, row(strings.TrimPrefix(strings.TrimPrefix(p.ImportPath, r.Module), "/"), p.Kinds, p.Total))
+	}
+	rows = append(rows, row("total", r.Kinds, r.Total))
+
+	switch cfg.OutputFormat {
+	case Json:
+		buf, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal json: %w", err)
+		}
+		return buf, nil
+	case Text:
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "documentation coverage of %s:\n", r.Module)
+		w := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', tabwriter.AlignRight)
+		for _, cols := range rows {
+			fmt.Fprintln(w, strings.Join(cols, "\t")+"\t")
+		}
+		if err := w.Flush(); err != nil {
+			return nil, err
+		}
+		return []byte(sb.String()), nil
+	case Adoc:
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "= Documentation coverage of %s\n\n", r.Module)
+		fmt.Fprintf(&sb, "[cols=\"3%s\",options=\"header\"]\n|===\n", strings.Repeat(",2", len(header)-1))
+		for _, cols := range rows {
+			sb.WriteString("|" + strings.Join(cols, " |") + "\n")
+		}
+		sb.WriteString("|===\n")
+		return []byte(sb.String()), nil
+	default:
+		return nil, fmt.Errorf("invalid output format: %s", cfg.OutputFormat)
+	}
+}
@@ -0,0 +1,22 @@
+package app
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+)
+
+// DeprecatedUsages loads the configured module and returns all usages of its deprecated declarations within the
+// module itself.
+func DeprecatedUsages(cfg Config) ([]golang.DeprecatedUsage, error) {
+	node, err := Load(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	usages, err := golang.DeprecatedUsages(cfg.ModPath, node)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find usages of deprecated declarations in %s: %w", node.Name, err)
+	}
+
+	return usages, nil
+}
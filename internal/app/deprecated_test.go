@@ -0,0 +1,46 @@
+package app
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/fixture"
+	"os"
+	"path/filepath"
+)
+
+func ExampleDeprecatedUsages() {
+	dir, err := os.MkdirTemp("", "deprecated")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := fixture.Copy(dir); err != nil {
+		panic(err)
+	}
+
+	// Field of package store calls the deprecated Index
+	src := `package store
+
+import storeindex "example.com/m/store-index"
+
+// Field returns the indexed field.
+func Field() string {
+	return storeindex.Index()
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "store", "field.go"), []byte(src), 0644); err != nil {
+		panic(err)
+	}
+
+	usages, err := DeprecatedUsages(Config{ModPath: dir, PkgSep: "/"})
+	if err != nil {
+		panic(err)
+	}
+
+	for _, u := range usages {
+		u.Position.Filename, _ = filepath.Rel(dir, u.Position.Filename)
+		fmt.Println(u)
+	}
+	// Output:
+	// store/field.go:7:20: store-index.Index is deprecated: use the Field of the Index in store/index instead.
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+	"log"
+	"os"
+)
+
+// diagnose returns the unresolved references of the resolved module and writes them to the configured report. In
+// strict mode, any diagnostic is an error, which is returned together with the diagnostics.
+func diagnose(cfg Config, node *api.Module) ([]golang.Diagnostic, error) {
+	diagnostics := golang.Diagnose(node)
+
+	if cfg.Diagnostics != "" {
+		buf, err := json.MarshalIndent(diagnostics, "", "  ")
+		if err != nil {
+			return diagnostics, fmt.Errorf("cannot marshal json: %w", err)
+		}
+
+		if err := os.WriteFile(cfg.Diagnostics, buf, 0644); err != nil {
+			return diagnostics, fmt.Errorf("cannot write diagnostics: %w", err)
+		}
+	}
+
+	if cfg.Strict && len(diagnostics) > 0 {
+		return diagnostics, fmt.Errorf("%d unresolved references in %s", len(diagnostics), node.Name)
+	}
+
+	return diagnostics, nil
+}
+
+// warn logs the diagnostics for the command line, as errors in strict mode and as warnings otherwise.
+func warn(cfg Config, diagnostics []golang.Diagnostic) {
+	level := "warning"
+	if cfg.Strict {
+		level = "error"
+	}
+
+	for _, d := range diagnostics {
+		log.Printf("%s: %s", level, d)
+	}
+}
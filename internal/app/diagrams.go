@@ -0,0 +1,176 @@
+package app
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/diagram"
+	gendiagram "github.com/worldiety/gdoc/internal/generator/diagram"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+	"html/template"
+	"strings"
+)
+
+// moduleDiagrams returns all diagrams, which describe the module as a whole.
+func moduleDiagrams(cfg Config, node *api.Module) []diagram.Diagram {
+	var collapse []string
+	for _, prefix := range strings.Split(cfg.GraphCollapse, ";") {
+		if prefix != "" {
+			collapse = append(collapse, prefix)
+		}
+	}
+
+	res := []diagram.Diagram{
+		diagram.NewImportGraph(node, diagram.ImportGraphOptions{
+			Collapse: collapse,
+			Stdlib:   cfg.GraphStdlib,
+			External: cfg.GraphExternal,
+			Cycles:   cfg.GraphCycles,
+		}),
+	}
+
+	if constructors, _ := diagram.NewConstructorGraph(node); len(constructors.Nodes) > 0 {
+		res = append(res, constructors)
+	}
+
+	return res
+}
+
+// wiringOrder returns the table rows of the constructor wiring order.
+func wiringOrder(node *api.Module) golang.AWiringOrder {
+	_, wiring := diagram.NewConstructorGraph(node)
+
+	var res golang.AWiringOrder
+	for _, w := range wiring {
+		row := golang.AWiring{
+			Type:        golang.NewARefId(w.Type, node.Anchor),
+			Constructor: golang.NewARefId(w.Constructor, node.Anchor),
+			Cyclic:      w.Cyclic,
+		}
+
+		for _, r := range w.Requires {
+			row.Requires = append(row.Requires, golang.NewARefId(r, node.Anchor))
+		}
+
+		res = append(res, row)
+	}
+
+	return res
+}
+
+// DiagramExtension returns true, if asciidoctor-diagram is required to render the embedded diagrams.
+func (c Config) DiagramExtension() bool {
+	format, err := gendiagram.ParseFormat(c.Diagrams)
+	return err == nil && format != "" && format != gendiagram.Svg
+}
+
+// packageDiagrams returns all diagrams, which describe the given package.
+func packageDiagrams(node *api.Module, p *api.Package) []diagram.Diagram {
+	var res []diagram.Diagram
+	if classes := diagram.NewClassDiagram(node, p); len(classes.Classes) > 0 {
+		res = append(res, classes)
+	}
+
+	if calls := diagram.NewCallGraph(node, p); len(calls.Edges) > 0 {
+		res = append(res, calls)
+	}
+
+	return res
+}
+
+// renderDiagrams renders the diagrams in the configured format, which is empty if diagrams are disabled.
+func renderDiagrams(cfg Config, diagrams []diagram.Diagram) (gendiagram.Format, []string, error) {
+	format, err := gendiagram.ParseFormat(cfg.Diagrams)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if format == "" {
+		return "", nil, nil
+	}
+
+	var res []string
+	for _, d := range diagrams {
+		src, err := gendiagram.Render(format, d)
+		if err != nil {
+			return "", nil, fmt.Errorf("cannot render diagram %s: %w", d.Head().Name, err)
+		}
+
+		res = append(res, src)
+	}
+
+	return format, res, nil
+}
+
+func asciidocDiagrams(cfg Config, diagrams []diagram.Diagram, level int) ([]golang.ADiagram, error) {
+	format, sources, err := renderDiagrams(cfg, diagrams)
+	if err != nil || format == "" {
+		return nil, err
+	}
+
+	var res []golang.ADiagram
+	for i, d := range diagrams {
+		res = append(res, golang.ADiagram{
+			Title:  d.Head().Title,
+			Level:  level,
+			Name:   d.Head().Name,
+			Lang:   format.Block(),
+			Source: sources[i],
+			Svg:    format == gendiagram.Svg,
+		})
+	}
+
+	return res, nil
+}
+
+// htmlDiagrams returns the inline svg images of the module and of each package. Other formats require external
+// tools and are not embedded.
+func htmlDiagrams(cfg Config, node *api.Module) (map[api.ImportPath][]template.HTML, error) {
+	format, err := gendiagram.ParseFormat(cfg.Diagrams)
+	if err != nil || format != gendiagram.Svg {
+		return nil, err
+	}
+
+	res := map[api.ImportPath][]template.HTML{}
+	add := func(path api.ImportPath, diagrams []diagram.Diagram) error {
+		_, sources, err := renderDiagrams(cfg, diagrams)
+		if err != nil {
+			return err
+		}
+
+		for _, src := range sources {
+			res[path] = append(res[path], template.HTML(src))
+		}
+		return nil
+	}
+
+	if err := add("", moduleDiagrams(cfg, node)); err != nil {
+		return nil, err
+	}
+
+	for path, p := range node.Packages {
+		if err := add(path, packageDiagrams(node, p)); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+func diagramFiles(cfg Config, node *api.Module) (map[string][]byte, error) {
+	diagrams := moduleDiagrams(cfg, node)
+	for _, p := range node.Packages {
+		diagrams = append(diagrams, packageDiagrams(node, p)...)
+	}
+
+	format, sources, err := renderDiagrams(cfg, diagrams)
+	if err != nil || format == "" {
+		return nil, err
+	}
+
+	res := map[string][]byte{}
+	for i, d := range diagrams {
+		res[d.Head().Name+format.Ext()] = []byte(sources[i])
+	}
+
+	return res, nil
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/apidiff"
+	"github.com/worldiety/gdoc/internal/generator/changelog"
+	"github.com/worldiety/gdoc/internal/git"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+	"log"
+)
+
+const Markdown = "markdown"
+
+// DiffConfig configures the comparison of two versions of a module, see Diff.
+type DiffConfig struct {
+	ModPath      string
+	OutputFormat string
+	Old, New     string // git refs
+}
+
+func (c *DiffConfig) Reset() {
+	wd, err := golang.ModWdRoot()
+	if err != nil {
+		log.Fatal(fmt.Errorf("could not walk to mod root directory: %w", err))
+	}
+
+	c.ModPath = wd
+	c.OutputFormat = Adoc
+}
+
+func (c *DiffConfig) Flags(flags *flag.FlagSet) {
+	flags.StringVar(&c.ModPath, "modPath", c.ModPath, "the modules path to use")
+	flags.StringVar(&c.OutputFormat, "format", c.OutputFormat, "default is adoc. markdown|json are available as well")
+}
+
+// Diff checks out both configured git refs into temporary worktrees, parses them and renders the API changes.
+func Diff(cfg DiffConfig) ([]byte, error) {
+	report, err := LoadDiff(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.OutputFormat {
+	case Adoc:
+		return []byte(changelog.Asciidoc(report)), nil
+	case Markdown:
+		return []byte(changelog.Markdown(report)), nil
+	case Json:
+		buf, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal json: %w", err)
+		}
+		return buf, nil
+	default:
+		return nil, fmt.Errorf("invalid output format: %s", cfg.OutputFormat)
+	}
+}
+
+// LoadDiff parses both configured git refs and compares them.
+func LoadDiff(cfg DiffConfig) (*apidiff.Report, error) {
+	old, err := LoadRef(cfg.ModPath, cfg.Old)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := LoadRef(cfg.ModPath, cfg.New)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apidiff.Report{
+		Module:  node.Name,
+		Old:     cfg.Old,
+		New:     cfg.New,
+		Changes: apidiff.Compare(old, node),
+	}, nil
+}
+
+// LoadRef parses the module at the given git ref. Types are not resolved, because older versions may
+// not build anymore.
+func LoadRef(modPath, ref string) (*api.Module, error) {
+	dir, remove, err := git.Worktree(modPath, ref)
+	if err != nil {
+		return nil, fmt.Errorf("cannot check out %s: %w", ref, err)
+	}
+
+	defer func() {
+		if err := remove(); err != nil {
+			log.Printf("cannot remove worktree of %s: %v", ref, err)
+		}
+	}()
+
+	node, err := golang.Parse(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", ref, err)
+	}
+
+	return node, nil
+}
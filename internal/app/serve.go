@@ -0,0 +1,277 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/generator/html"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+	"github.com/worldiety/gdoc/internal/server"
+	"io/fs"
+	"log"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ServeConfig configures the documentation server, see Serve.
+type ServeConfig struct {
+	Config
+	Addr     string
+	Interval time.Duration // the polling interval of the file watcher
+}
+
+func (c *ServeConfig) Reset() {
+	c.Config.Reset()
+	c.OutputFormat = Native
+	c.Addr = "localhost:8080"
+	c.Interval = 500 * time.Millisecond
+}
+
+func (c *ServeConfig) Flags(flags *flag.FlagSet) {
+	c.Config.Flags(flags)
+	flags.StringVar(&c.Addr, "addr", c.Addr, "the address to listen on")
+	flags.DurationVar(&c.Interval, "interval", c.Interval, "how often to look for changed files")
+}
+
+// Serve renders the native html documentation into memory and serves it, until the server fails. Whenever a go
+// file, a readme, go.mod or the glossary changes, only the affected packages are parsed and type checked again and
+// the browsers reload the page. The call graph is still built from all packages, see Config CallDepth.
+func Serve(cfg ServeConfig) error {
+	modRoot, err := golang.ModRoot(cfg.ModPath)
+	if err != nil {
+		return err
+	}
+
+	w := &watcher{cfg: cfg, root: modRoot, parsed: map[api.ImportPath]*api.Package{}}
+	w.files, err = w.scan()
+	if err != nil {
+		return err
+	}
+
+	srv := server.New()
+	node, page, err := w.build(nil)
+	if err != nil {
+		return err
+	}
+	srv.Update(node, page)
+
+	ticker := time.NewTicker(cfg.Interval)
+	done := make(chan struct{})
+	defer func() {
+		ticker.Stop()
+		close(done)
+	}()
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				w.reload(srv)
+			}
+		}
+	}()
+
+	log.Printf("serving %s on http://%s", w.module.Name, cfg.Addr)
+	return http.ListenAndServe(cfg.Addr, srv)
+}
+
+// fileState identifies a version of a watched file.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// watcher polls the module for changes and keeps the parsed and type checked packages, so that only changed ones
+// are parsed and checked again.
+type watcher struct {
+	cfg     ServeConfig
+	root    string
+	files   map[string]fileState
+	parsed  map[api.ImportPath]*api.Package // the parsed but not yet resolved packages, because resolving modifies them
+	checker *golang.Checker
+	module  api.Module // the module without packages
+}
+
+// reload builds the changed packages, if any, and updates the server.
+func (w *watcher) reload(srv *server.Server) {
+	pkgs, full, err := w.poll()
+	if err != nil {
+		log.Printf("cannot watch %s: %v", w.root, err)
+		return
+	}
+
+	if !full && len(pkgs) == 0 {
+		return
+	}
+
+	changed := strings.Join(pkgs, ", ")
+	if full {
+		pkgs = nil
+		changed = "all packages"
+	}
+
+	node, page, err := w.build(pkgs)
+	if err != nil {
+		log.Print(err)
+		srv.Fail(err)
+		return
+	}
+
+	log.Printf("reloaded %s", changed)
+	srv.Update(node, page)
+}
+
+// scan returns the state of all watched files of the module.
+func (w *watcher) scan() (map[string]fileState, error) {
+	res := map[string]fileState{}
+	err := filepath.WalkDir(w.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path != w.root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		name := strings.ToLower(d.Name())
+		if !strings.HasSuffix(name, ".go") && name != "readme.md" && name != "go.mod" && name != "glossary.yaml" {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		res[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot scan %s: %w", w.root, err)
+	}
+
+	return res, nil
+}
+
+// poll returns the import paths of the changed packages. Changes of module wide files like go.mod require to parse
+// the entire module.
+func (w *watcher) poll() (pkgs []api.ImportPath, full bool, err error) {
+	files, err := w.scan()
+	if err != nil {
+		return nil, false, err
+	}
+
+	changed := map[string]bool{}
+	for path, state := range files {
+		if old, ok := w.files[path]; !ok || old != state {
+			changed[path] = true
+		}
+	}
+	for path := range w.files {
+		if _, ok := files[path]; !ok {
+			changed[path] = true
+		}
+	}
+	w.files = files
+
+	dirs := map[api.ImportPath]bool{}
+	for path := range changed {
+		dir := filepath.Dir(path)
+		if !strings.HasSuffix(path, ".go") && dir == w.root {
+			full = true
+			continue
+		}
+
+		rel, err := filepath.Rel(w.root, dir)
+		if err != nil {
+			return nil, false, fmt.Errorf("cannot relate %s to %s: %w", dir, w.root, err)
+		}
+
+		// same as the import paths of the parser
+		dirs[w.module.Name+"/"+rel] = true
+	}
+
+	for path := range dirs {
+		pkgs = append(pkgs, path)
+	}
+	sort.Strings(pkgs)
+
+	return pkgs, full, nil
+}
+
+// build parses and type checks the given packages or the entire module, if none are given, resolves the module
+// and renders it.
+func (w *watcher) build(pkgs []api.ImportPath) (*api.Module, []byte, error) {
+	m, err := golang.Parse(w.root, pkgs...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot parse from %s: %w", w.root, err)
+	}
+
+	if len(pkgs) == 0 {
+		w.parsed = map[api.ImportPath]*api.Package{}
+		w.checker = golang.NewChecker(w.root, m.Name)
+	}
+	w.module.Name = m.Name
+	w.module.Readme = m.Readme
+
+	for _, path := range pkgs {
+		delete(w.parsed, path)
+	}
+	w.checker.Forget(pkgs...)
+
+	for path, p := range m.Packages {
+		w.parsed[path] = p
+	}
+
+	node := w.module
+	node.Packages = map[api.ImportPath]*api.Package{}
+	var paths []api.ImportPath
+	for path, p := range w.parsed {
+		node.Packages[path] = p.Clone()
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	// the glossary collects the terms of all packages
+	node.Glossary, err = golang.NewGlossary(w.root, &node)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	loaded, err := w.checker.Packages(paths...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot type check %s: %w", node.Name, err)
+	}
+	golang.ResolvePackages(&node, loaded)
+
+	if err := annotate(context.Background(), w.cfg.Config, &node); err != nil {
+		return nil, nil, err
+	}
+
+	if err := link(w.cfg.Config, &node); err != nil {
+		return nil, nil, err
+	}
+
+	diagnostics, err := diagnose(w.cfg.Config, &node)
+	warn(w.cfg.Config, diagnostics)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	page, err := renderNative(w.cfg.Config, &node, html.Options{Head: server.ReloadScript})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &node, page, nil
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/fixture"
+	"os"
+	"path/filepath"
+)
+
+func Example_watcher() {
+	dir, err := os.MkdirTemp("", "serve")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := fixture.Copy(dir); err != nil {
+		panic(err)
+	}
+
+	w := &watcher{cfg: ServeConfig{Config: Config{ModPath: dir, OutputFormat: Native}}, root: dir}
+	if w.files, err = w.scan(); err != nil {
+		panic(err)
+	}
+
+	if _, _, err := w.build(nil); err != nil {
+		panic(err)
+	}
+
+	index := filepath.Join(dir, "store", "index", "index.go")
+	buf, err := os.ReadFile(index)
+	if err != nil {
+		panic(err)
+	}
+
+	buf = append(buf, "\n//gdoc:term Shard: a part of an index\n"...)
+	if err := os.WriteFile(index, buf, 0644); err != nil {
+		panic(err)
+	}
+
+	pkgs, full, err := w.poll()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(pkgs, full)
+
+	node, _, err := w.build(pkgs)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, term := range node.Glossary {
+		fmt.Println(term.Name)
+	}
+
+	// the unchanged package keeps the fields, which are not marshalled
+	store := node.Packages[fixture.Name+"/store"]
+	fmt.Println(store.Structs["Store"].Fields[0].ParentStruct == store.Structs["Store"])
+	fmt.Println(store.Consts[0].Content[0].Value)
+	// Output:
+	// [example.com/m/store/index] false
+	// Entity
+	// Repository
+	// Shard
+	// Tenant
+	// Wiring
+	// true
+	// iota
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/generator/terminal"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// ShowConfig configures the terminal viewer, see Show.
+type ShowConfig struct {
+	ModPath    string
+	Plain      bool
+	Source     string
+	SourceRepo string
+}
+
+func (c *ShowConfig) Reset() {
+	wd, err := golang.ModWdRoot()
+	if err != nil {
+		log.Fatal(fmt.Errorf("could not walk to mod root directory: %w", err))
+	}
+
+	c.ModPath = wd
+	c.Plain = os.Getenv("NO_COLOR") != ""
+}
+
+func (c *ShowConfig) Flags(flags *flag.FlagSet) {
+	flags.StringVar(&c.ModPath, "modPath", c.ModPath, "the modules path to use")
+	flags.BoolVar(&c.Plain, "plain", c.Plain, "print plain text without colors and hyperlinks, e.g. for pagers. Default is true, if NO_COLOR is set")
+	flags.StringVar(&c.Source, "source", c.Source, "if not empty, link to the code host instead of the local files. See the -source flag of gdoc")
+	flags.StringVar(&c.SourceRepo, "sourceRepo", c.SourceRepo, "the web url of the repository for -source. Default is derived from the origin remote")
+}
+
+// Show loads the module and renders the package or symbol of the query for the terminal. Declarations link to
+// their local files, unless a code host is configured.
+func Show(cfg ShowConfig, query string) ([]byte, error) {
+	node, err := golang.Parse(cfg.ModPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse from %s: %w", cfg.ModPath, err)
+	}
+
+	if err := golang.Resolve(cfg.ModPath, node); err != nil {
+		return nil, fmt.Errorf("cannot resolve %s: %w", node.Name, err)
+	}
+	golang.LinkComments(node)
+
+	// the bodies of package level functions contain the examples
+	c := Config{ModPath: cfg.ModPath, Bodies: funcStereotype, Source: cfg.Source, SourceRepo: cfg.SourceRepo}
+	if err := addBodies(c, node); err != nil {
+		return nil, fmt.Errorf("cannot read the examples of %s: %w", node.Name, err)
+	}
+
+	if cfg.Source != "" {
+		if err := addSourceURLs(c, node); err != nil {
+			return nil, fmt.Errorf("cannot link the sources of %s: %w", node.Name, err)
+		}
+	} else if err := addFileURLs(cfg.ModPath, node); err != nil {
+		return nil, err
+	}
+
+	s, err := terminal.Show(node, query, terminal.Options{Plain: cfg.Plain})
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(s), nil
+}
+
+// addFileURLs links each position to its local file.
+func addFileURLs(modPath string, node *api.Module) error {
+	modRoot, err := golang.ModRoot(modPath)
+	if err != nil {
+		return err
+	}
+
+	positions(node, func(p *api.Position) {
+		if p.Valid() {
+			p.URL = (&url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(modRoot, p.File))}).String()
+		}
+	})
+
+	return nil
+}
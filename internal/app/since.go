@@ -0,0 +1,82 @@
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/apidiff"
+	"github.com/worldiety/gdoc/internal/git"
+	"os"
+	"path/filepath"
+)
+
+// snapshotFormat is part of each cache key and must be incremented, whenever the parsed model changes.
+const snapshotFormat = "5"
+
+// addSince annotates each declaration with the first semver tag, which declares it.
+func addSince(cfg Config, node *api.Module) error {
+	tags, err := git.Tags(cfg.ModPath)
+	if err != nil {
+		return err
+	}
+
+	var releases []apidiff.Release
+	for _, tag := range tags {
+		m, err := loadRelease(cfg, tag)
+		if err != nil {
+			return err
+		}
+
+		releases = append(releases, apidiff.Release{Version: tag, Module: m})
+	}
+
+	apidiff.AddSince(node, releases)
+	return nil
+}
+
+// loadRelease parses the module at the given tag or reads it from the snapshot cache. Snapshots are keyed by the
+// commit, so moved tags are parsed again.
+func loadRelease(cfg Config, tag string) (*api.Module, error) {
+	commit, err := git.Run(cfg.ModPath, "rev-parse", tag+"^{commit}")
+	if err != nil {
+		return nil, err
+	}
+
+	var file string
+	if cfg.CacheDir != "" {
+		abs, err := filepath.Abs(cfg.ModPath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot determine path of %s: %w", cfg.ModPath, err)
+		}
+
+		key := sha256.Sum224([]byte(snapshotFormat + abs + commit))
+		file = filepath.Join(cfg.CacheDir, "snapshots", hex.EncodeToString(key[:])+".json")
+		if m, err := loadSnapshot(file); err == nil {
+			return m, nil
+		}
+	}
+
+	m, err := LoadRef(cfg.ModPath, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if file != "" {
+		buf, err := json.Marshal(m)
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal snapshot of %s: %w", tag, err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return nil, fmt.Errorf("cannot create snapshot cache: %w", err)
+		}
+
+		if err := os.WriteFile(file, buf, 0644); err != nil {
+			return nil, fmt.Errorf("cannot write snapshot of %s: %w", tag, err)
+		}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/git"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// sourcePresets are the url templates of the supported code hosts, see Config.Source.
+var sourcePresets = map[string]string{
+	"github":    "{repo}/blob/{commit}/{path}#L{line}-L{endLine}",
+	"gitlab":    "{repo}/-/blob/{commit}/{path}#L{line}-{endLine}",
+	"gitea":     "{repo}/src/commit/{commit}/{path}#L{line}-L{endLine}",
+	"bitbucket": "{repo}/src/{commit}/{path}#lines-{line}:{endLine}",
+	"file":      "file://{root}/{path}",
+}
+
+// addSourceURLs sets the url of each position according to the configured template. The commit is read from the
+// local git directory.
+func addSourceURLs(cfg Config, node *api.Module) error {
+	tmpl, ok := sourcePresets[cfg.Source]
+	if !ok {
+		tmpl = cfg.Source
+	}
+
+	root, _, err := git.Repository(cfg.ModPath)
+	if err != nil {
+		return err
+	}
+
+	commit, err := git.Head(cfg.ModPath)
+	if err != nil {
+		return err
+	}
+
+	repo := cfg.SourceRepo
+	if repo == "" && strings.Contains(tmpl, "{repo}") {
+		remote, err := git.RemoteURL(cfg.ModPath, "origin")
+		if err != nil {
+			return fmt.Errorf("cannot determine the repository url, use -sourceRepo: %w", err)
+		}
+		repo = git.WebURL(remote)
+	}
+
+	modRoot, err := golang.ModRoot(cfg.ModPath)
+	if err != nil {
+		return err
+	}
+
+	// positions are relative to the module, which may be nested in the repository
+	prefix, err := filepath.Rel(root, modRoot)
+	if err != nil {
+		return fmt.Errorf("cannot relate %s to %s: %w", modRoot, root, err)
+	}
+
+	r := strings.NewReplacer("{repo}", strings.TrimSuffix(repo, "/"), "{commit}", commit, "{root}", filepath.ToSlash(root))
+	positions(node, func(p *api.Position) {
+		if !p.Valid() {
+			return
+		}
+
+		p.URL = strings.NewReplacer(
+			"{path}", path.Join(filepath.ToSlash(prefix), p.File),
+			"{line}", strconv.Itoa(p.Line),
+			"{endLine}", strconv.Itoa(p.EndLine),
+		).Replace(r.Replace(tmpl))
+	})
+
+	return nil
+}
+
+// positions calls fn for the position of each package and declaration of the module.
+func positions(node *api.Module, fn func(p *api.Position)) {
+	for _, p := range node.Packages {
+		fn(&p.Position)
+		for _, block := range p.Consts {
+			for i := range block.Content {
+				fn(&block.Content[i].Position)
+			}
+		}
+		for _, v := range p.Vars {
+			fn(&v.Position)
+		}
+		for _, f := range p.Functions {
+			fn(&f.Position)
+		}
+		for _, s := range p.Structs {
+			fn(&s.Position)
+			for _, f := range s.Constructors {
+				fn(&f.Position)
+			}
+			for _, m := range s.Methods {
+				fn(&m.Position)
+			}
+			for _, f := range s.Fields {
+				fn(&f.Position)
+			}
+		}
+	}
+}
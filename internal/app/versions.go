@@ -0,0 +1,168 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/apidiff"
+	"github.com/worldiety/gdoc/internal/generator/html"
+	"github.com/worldiety/gdoc/internal/generator/search"
+	"github.com/worldiety/gdoc/internal/git"
+	"log"
+	"path"
+	"strings"
+)
+
+// versionsManifest is the name of the file, which lists all generated versions.
+const versionsManifest = "versions.json"
+
+// ManifestEntry describes a generated version in the versions.json manifest.
+type ManifestEntry struct {
+	Version string `json:"version"`
+	Path    string `json:"path"` // the directory relative to the manifest
+	Latest  bool   `json:"latest"`
+}
+
+// Versions generates the native html documentation for each configured git ref. The refs are expected in
+// ascending order, thus the last one is the latest. The returned files are keyed by their path relative to
+// the output directory, which contains a directory per version and the versions.json manifest.
+func Versions(cfg Config) (map[string][]byte, error) {
+	var refs []string
+	for _, ref := range strings.Split(cfg.Versions, ";") {
+		if ref = strings.TrimSpace(ref); ref != "" {
+			refs = append(refs, ref)
+		}
+	}
+
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("no versions configured")
+	}
+
+	nodes := make([]*api.Module, len(refs))
+	for i, ref := range refs {
+		node, err := loadVersion(cfg, ref)
+		if err != nil {
+			return nil, err
+		}
+		nodes[i] = node
+	}
+
+	// links between the versions require the same anchors, thus the comments are linked after loading all of them
+	if err := link(cfg, nodes...); err != nil {
+		return nil, err
+	}
+
+	for _, node := range nodes {
+		diagnostics, err := diagnose(cfg, node)
+		warn(cfg, diagnostics)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	legacy, err := anchorFiles(nodes[len(nodes)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	// link changed symbols to the previous and next version in both directions
+	changes := make([]map[string][]html.VersionLink, len(refs))
+	for i := range changes {
+		changes[i] = map[string][]html.VersionLink{}
+	}
+
+	for i := 1; i < len(refs); i++ {
+		old, node := nodes[i-1], nodes[i]
+		for _, c := range apidiff.Compare(old, node) {
+			if c.Kind != apidiff.Changed || c.Name == "" {
+				continue
+			}
+
+			newID := node.Anchor(api.NewRefID(c.Package, c.Name))
+			oldID := old.Anchor(api.NewRefID(old.Name+strings.TrimPrefix(c.Package, node.Name), c.Name))
+			changes[i][newID] = append(changes[i][newID], html.VersionLink{
+				Version: refs[i-1],
+				Href:    versionHref(refs[i-1]) + "#" + oldID,
+			})
+			changes[i-1][oldID] = append(changes[i-1][oldID], html.VersionLink{
+				Version: refs[i],
+				Href:    versionHref(refs[i]) + "#" + newID,
+			})
+		}
+	}
+
+	res := map[string][]byte{}
+	var manifest []ManifestEntry
+	for i, ref := range refs {
+		opts := html.Options{Version: ref, Changes: changes[i]}
+		for _, other := range refs {
+			opts.Versions = append(opts.Versions, html.Version{Name: other, Href: versionHref(other), Current: other == ref})
+		}
+
+		buf, err := renderNative(cfg, nodes[i], opts)
+		if err != nil {
+			return nil, fmt.Errorf("cannot render %s: %w", ref, err)
+		}
+
+		files, err := search.Files(nodes[i])
+		if err != nil {
+			return nil, fmt.Errorf("cannot index %s: %w", ref, err)
+		}
+
+		dir := versionDir(ref)
+		res[path.Join(dir, "index.html")] = buf
+		for name, buf := range files {
+			res[path.Join(dir, name)] = buf
+		}
+		for name, buf := range legacy {
+			res[path.Join(dir, name)] = buf
+		}
+		manifest = append(manifest, ManifestEntry{Version: ref, Path: dir, Latest: i == len(refs)-1})
+	}
+
+	buf, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal json: %w", err)
+	}
+	res[versionsManifest] = buf
+
+	// the root page forwards to the latest version
+	latest := path.Join(versionDir(refs[len(refs)-1]), "index.html")
+	res["index.html"] = []byte(`<!DOCTYPE html><meta charset="utf-8"><meta http-equiv="refresh" content="0; url=` + latest + `">` + "\n")
+
+	return res, nil
+}
+
+// loadVersion checks out the ref into a temporary worktree and loads it like the working tree, but without linking
+// the comments.
+func loadVersion(cfg Config, ref string) (*api.Module, error) {
+	dir, remove, err := git.Worktree(cfg.ModPath, ref)
+	if err != nil {
+		return nil, fmt.Errorf("cannot check out %s: %w", ref, err)
+	}
+
+	defer func() {
+		if err := remove(); err != nil {
+			log.Printf("cannot remove worktree of %s: %v", ref, err)
+		}
+	}()
+
+	cfg.ModPath = dir
+	node, err := load(context.Background(), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load %s: %w", ref, err)
+	}
+
+	return node, nil
+}
+
+// versionDir returns the directory of a version, which must not be nested like branch names may be.
+func versionDir(ref string) string {
+	return strings.NewReplacer("/", "-", "\\", "-", ":", "-").Replace(ref)
+}
+
+// versionHref returns the link from one version page to the other.
+func versionHref(ref string) string {
+	return "../" + versionDir(ref) + "/index.html"
+}
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/fixture"
+	"github.com/worldiety/gdoc/internal/git"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+func ExampleVersions() {
+	dir, err := os.MkdirTemp("", "versions")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// v1 is the example module and v2 adds a parameter to NewStore
+	if err := fixture.Copy(dir); err != nil {
+		panic(err)
+	}
+	commit := func(tag string) {
+		for _, args := range [][]string{
+			{"add", "-A"},
+			{"-c", "user.name=gdoc", "-c", "user.email=gdoc@example.com", "commit", "-q", "-m", tag},
+			{"tag", tag},
+		} {
+			if _, err := git.Run(dir, args...); err != nil {
+				panic(err)
+			}
+		}
+	}
+	if _, err := git.Run(dir, "init", "-q"); err != nil {
+		panic(err)
+	}
+	commit("v1")
+
+	file := filepath.Join(dir, "store", "store.go")
+	src, err := os.ReadFile(file)
+	if err != nil {
+		panic(err)
+	}
+	src = []byte(strings.Replace(string(src), "NewStore(mode Mode)", "NewStore(mode Mode, size int)", 1))
+	if err := os.WriteFile(file, src, 0644); err != nil {
+		panic(err)
+	}
+	commit("v2")
+
+	files, err := Versions(Config{ModPath: dir, Versions: "v1;v2", Anchors: ReadableAnchors, PkgSep: "/"})
+	if err != nil {
+		panic(err)
+	}
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Println(strings.Join(names, " "))
+	fmt.Println(string(files[versionsManifest]))
+
+	// each version selects itself and links the changed NewStore to the other one
+	selected := regexp.MustCompile(`<option value="[^"]*" selected>\w+`)
+	changed := regexp.MustCompile(`<a class="changed" href="[^"]*">\w+</a>`)
+	for _, name := range []string{"v1/index.html", "v2/index.html"} {
+		fmt.Println(selected.FindString(string(files[name])), changed.FindAllString(string(files[name]), -1))
+	}
+	// Output:
+	// index.html v1/anchors.json v1/index.html v1/search.js v1/search.json v2/anchors.json v2/index.html v2/search.js v2/search.json versions.json
+	// [
+	//   {
+	//     "version": "v1",
+	//     "path": "v1",
+	//     "latest": false
+	//   },
+	//   {
+	//     "version": "v2",
+	//     "path": "v2",
+	//     "latest": true
+	//   }
+	// ]
+	// <option value="../v1/index.html" selected>v1 [<a class="changed" href="../v2/index.html#store--NewStore">v2</a>]
+	// <option value="../v2/index.html" selected>v2 [<a class="changed" href="../v1/index.html#store--NewStore">v1</a>]
+}
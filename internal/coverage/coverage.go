@@ -0,0 +1,135 @@
+// Package coverage measures, how many exported declarations of a module are documented.
+package coverage
+
+import (
+	"github.com/worldiety/gdoc/internal/api"
+	"sort"
+	"strings"
+)
+
+// Kinds returns the measured symbol kinds in the order of the report columns.
+func Kinds() []api.SymbolKind {
+	return []api.SymbolKind{api.SymbolType, api.SymbolFunc, api.SymbolMethod, api.SymbolField, api.SymbolConst, api.SymbolVar}
+}
+
+// Counts is the number of documented and of all declarations.
+type Counts struct {
+	Documented int
+	Total      int
+}
+
+// Percent returns the documented share in percent. Nothing to document counts as fully documented.
+func (c Counts) Percent() float64 {
+	if c.Total == 0 {
+		return 100
+	}
+	return float64(c.Documented) * 100 / float64(c.Total)
+}
+
+func (c *Counts) add(o Counts) {
+	c.Documented += o.Documented
+	c.Total += o.Total
+}
+
+// Package is the coverage of a single package. Undocumented contains the names of the undocumented declarations,
+// methods and fields are named like Type.Method.
+type Package struct {
+	ImportPath   api.ImportPath
+	Kinds        map[api.SymbolKind]Counts
+	Total        Counts
+	Undocumented []string
+}
+
+// Report is the coverage of a module, whose packages are sorted by import path.
+type Report struct {
+	Module   string
+	Packages []Package
+	Kinds    map[api.SymbolKind]Counts
+	Total    Counts
+}
+
+// Compute counts the exported types, funcs, methods, fields, consts and vars of the module, which have a doc
+// comment or a line comment. Constants are also documented by the comment of their group.
+func Compute(m *api.Module) Report {
+	r := Report{Module: m.Name, Kinds: map[api.SymbolKind]Counts{}}
+	for path, p := range m.Packages {
+		r.Packages = append(r.Packages, newPackage(path, p))
+	}
+
+	sort.Slice(r.Packages, func(i, j int) bool {
+		return r.Packages[i].ImportPath < r.Packages[j].ImportPath
+	})
+
+	for _, p := range r.Packages {
+		for kind, c := range p.Kinds {
+			total := r.Kinds[kind]
+			total.add(c)
+			r.Kinds[kind] = total
+		}
+		r.Total.add(p.Total)
+	}
+
+	return r
+}
+
+func newPackage(path api.ImportPath, p *api.Package) Package {
+	res := Package{ImportPath: path, Kinds: map[api.SymbolKind]Counts{}}
+	count := func(kind api.SymbolKind, name string, comments ...string) {
+		c := res.Kinds[kind]
+		c.Total++
+		if documented(comments...) {
+			c.Documented++
+		} else {
+			res.Undocumented = append(res.Undocumented, name)
+		}
+		res.Kinds[kind] = c
+	}
+
+	for _, block := range p.Consts {
+		for _, c := range block.Content {
+			count(api.SymbolConst, c.RefId.Identifier, c.Comment, block.Doc)
+		}
+	}
+
+	for name, v := range p.Vars {
+		count(api.SymbolVar, name, v.Doc, v.Comment)
+	}
+
+	for name, fn := range p.Functions {
+		// tests and examples are no part of the api
+		if !strings.HasSuffix(fn.Position.File, "_test.go") {
+			count(api.SymbolFunc, name, fn.Comment)
+		}
+	}
+
+	for _, s := range p.Structs {
+		count(api.SymbolType, s.Name, s.Comment)
+		for _, fn := range s.Constructors {
+			count(api.SymbolFunc, fn.Name, fn.Comment)
+		}
+		for _, method := range s.Methods {
+			count(api.SymbolMethod, s.Name+"."+method.Name, method.Comment)
+		}
+		for _, f := range s.Fields {
+			if f.Name != "" {
+				count(api.SymbolField, s.Name+"."+f.Name, f.Doc, f.Comment)
+			}
+		}
+	}
+
+	for _, c := range res.Kinds {
+		res.Total.add(c)
+	}
+	sort.Strings(res.Undocumented)
+
+	return res
+}
+
+func documented(comments ...string) bool {
+	for _, c := range comments {
+		if strings.TrimSpace(c) != "" {
+			return true
+		}
+	}
+	return false
+}
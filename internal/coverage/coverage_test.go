@@ -0,0 +1,26 @@
+package coverage
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/fixture"
+)
+
+func ExampleCompute() {
+	m, err := fixture.Parse()
+	if err != nil {
+		panic(err)
+	}
+
+	r := Compute(m)
+	for _, p := range r.Packages {
+		fmt.Printf("%s %d/%d %v\n", p.ImportPath, p.Total.Documented, p.Total.Total, p.Undocumented)
+	}
+	fmt.Printf("consts %d/%d, vars %d/%d\n", r.Kinds[api.SymbolConst].Documented, r.Kinds[api.SymbolConst].Total,
+		r.Kinds[api.SymbolVar].Documented, r.Kinds[api.SymbolVar].Total)
+	// Output:
+	// example.com/m/store 8/9 [Strict]
+	// example.com/m/store-index 1/1 []
+	// example.com/m/store/index 2/2 []
+	// consts 3/3, vars 1/2
+}
@@ -0,0 +1,67 @@
+package diagram
+
+import (
+	"github.com/worldiety/gdoc/internal/api"
+	"sort"
+	"strings"
+)
+
+const callGraphPrefix = "calls-"
+
+// NewCallGraph creates the graph of direct calls between the exported functions and methods of a single package and
+// the exported functions and methods of the module, which they call or which call them. Indirect calls (see
+// api.CallRef Depth) are left out, because they are already implied by the direct ones.
+func NewCallGraph(m *api.Module, p *api.Package) *Graph {
+	g := &Graph{Header: Header{
+		Name:  callGraphPrefix + strings.ReplaceAll(strings.TrimPrefix(p.PackageDefinition.ImportPath, m.Name+"/"), "/", "-"),
+		Title: "Call graph",
+	}}
+
+	add := func(ref api.RefId) string {
+		kind := Internal
+		label := ref.Identifier
+		if ref.ImportPath != p.PackageDefinition.ImportPath {
+			kind = External
+			label = ref.PackageName() + "." + label
+		}
+
+		return g.AddNode(&Node{ID: refKey(ref), Label: label, Kind: kind, Link: m.Anchor(ref)}).ID
+	}
+
+	for _, fn := range sortedFunctions(p) {
+		for _, c := range fn.Calls {
+			if c.Depth == 1 {
+				g.AddEdge(add(fn.TypeDefinition), add(c.Target), "")
+			}
+		}
+
+		for _, c := range fn.CalledBy {
+			if c.Depth == 1 {
+				g.AddEdge(add(c.Target), add(fn.TypeDefinition), "")
+			}
+		}
+	}
+
+	return g
+}
+
+// sortedFunctions returns the functions, constructors and methods of the package in a stable order.
+func sortedFunctions(p *api.Package) []*api.Function {
+	var res []*api.Function
+	for _, fn := range p.Functions {
+		res = append(res, fn)
+	}
+
+	for _, s := range p.Structs {
+		res = append(res, s.Constructors...)
+		for _, method := range s.Methods {
+			res = append(res, method.Function)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].TypeDefinition.Identifier < res[j].TypeDefinition.Identifier
+	})
+
+	return res
+}
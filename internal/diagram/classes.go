@@ -0,0 +1,214 @@
+package diagram
+
+import (
+	"github.com/worldiety/gdoc/internal/api"
+	"sort"
+	"strings"
+)
+
+const classDiagramPrefix = "classes-"
+
+type RelationKind int
+
+const (
+	Composition RelationKind = iota // an embedded, pointer or value field of a custom type
+	Aggregation                     // a slice, array or map field containing a custom type
+	Realization                     // a type implements an interface
+)
+
+// ClassDiagram shows the types of a single package and their relations in the UML sense.
+type ClassDiagram struct {
+	Header
+	Classes   []*Class
+	Relations []*Relation
+}
+
+type Class struct {
+	ID       string // unique within the diagram, but not necessarily a valid identifier of the diagram languages
+	Name     string
+	Package  string // the package name, if declared in another package
+	Kind     NodeKind
+	Generics []string
+	Fields   []string
+	Methods  []string
+	Link     string // anchor id of the documented type
+	// Stereotypes contain either api.StereotypeStruct or api.StereotypeInterface, if known.
+	Stereotypes []api.Stereotype
+}
+
+// Interface returns true, if the class denotes an interface type.
+func (c *Class) Interface() bool {
+	for _, st := range c.Stereotypes {
+		if st == api.StereotypeInterface {
+			return true
+		}
+	}
+	return false
+}
+
+// QualifiedName returns the name including the package name, if the class is declared in another package.
+func (c *Class) QualifiedName() string {
+	if c.Package == "" {
+		return c.Name
+	}
+	return c.Package + "." + c.Name
+}
+
+type Relation struct {
+	From, To string // class ids
+	Kind     RelationKind
+	Label    string
+}
+
+// NewClassDiagram creates the class diagram of a single package. Types of other packages are only included,
+// if they are referenced by a relation.
+func NewClassDiagram(m *api.Module, p *api.Package) *ClassDiagram {
+	d := &ClassDiagram{Header: Header{
+		Name:  classDiagramPrefix + strings.ReplaceAll(strings.TrimPrefix(p.PackageDefinition.ImportPath, m.Name+"/"), "/", "-"),
+		Title: "Class diagram",
+	}}
+
+	var structs []*api.Struct
+	for _, s := range sortedStructs(p) {
+		if len(s.Stereotypes) > 0 || len(s.Methods) > 0 {
+			structs = append(structs, s)
+		}
+	}
+
+	for _, s := range structs {
+		d.addClass(newClass(m, s))
+	}
+
+	for _, s := range structs {
+		for _, f := range s.Fields {
+			d.addFieldRelation(m, s, f)
+		}
+
+		for _, ref := range s.Implements {
+			to := d.classOf(m, p, ref)
+			if to != nil {
+				d.Relations = append(d.Relations, &Relation{From: s.Name, To: to.ID, Kind: Realization})
+			}
+		}
+	}
+
+	return d
+}
+
+func newClass(m *api.Module, s *api.Struct) *Class {
+	c := &Class{
+		ID:          s.Name,
+		Name:        s.Name,
+		Kind:        Internal,
+		Link:        m.Anchor(s.TypeDefinition),
+		Stereotypes: s.Stereotypes,
+	}
+
+	for _, g := range s.Generics {
+		c.Generics = append(c.Generics, g.Name+" "+g.TypeDesc.SrcTypeDefinition)
+	}
+
+	for _, f := range s.Fields {
+		if f.Name == "" {
+			continue // embedded fields are shown as relation
+		}
+		c.Fields = append(c.Fields, f.Name+" "+f.TypeDesc.SrcTypeDefinition)
+	}
+
+	methods := make([]*api.Method, len(s.Methods))
+	copy(methods, s.Methods)
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].Name < methods[j].Name
+	})
+
+	for _, method := range methods {
+		sig := method.Signature
+		if sig == "" {
+			sig = method.Name + "()"
+		}
+		c.Methods = append(c.Methods, sig)
+	}
+
+	return c
+}
+
+func (d *ClassDiagram) class(id string) *Class {
+	for _, c := range d.Classes {
+		if c.ID == id {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func (d *ClassDiagram) addClass(c *Class) *Class {
+	if o := d.class(c.ID); o != nil {
+		return o
+	}
+
+	d.Classes = append(d.Classes, c)
+	return c
+}
+
+// classOf returns the class for the given type reference, which is added to the diagram if it is declared in
+// another package of the module. It returns nil for types, which are not declared in the module.
+func (d *ClassDiagram) classOf(m *api.Module, p *api.Package, ref api.RefId) *Class {
+	if ref.ImportPath == p.PackageDefinition.ImportPath {
+		return d.class(ref.Identifier)
+	}
+
+	other, ok := m.Packages[ref.ImportPath]
+	if !ok {
+		return nil
+	}
+
+	s, ok := other.Structs[ref.Identifier]
+	if !ok {
+		return nil
+	}
+
+	return d.addClass(&Class{
+		ID:          ref.ImportPath + "." + s.Name, // package names are not unique within a module
+		Name:        s.Name,
+		Package:     other.Name,
+		Kind:        External,
+		Link:        m.Anchor(s.TypeDefinition),
+		Stereotypes: s.Stereotypes,
+	})
+}
+
+func (d *ClassDiagram) addFieldRelation(m *api.Module, s *api.Struct, f *api.Field) {
+	p := m.Packages[s.TypeDefinition.ImportPath]
+	if p == nil {
+		return
+	}
+
+	td := f.TypeDesc
+	kind := Composition
+	if td.Map() {
+		if td.MapType == nil || td.MapType.ValueType == nil {
+			return
+		}
+		td = td.MapType.ValueType
+		kind = Aggregation
+	} else if td.Array() {
+		kind = Aggregation
+	}
+
+	if td.TypeOrigin != api.LocalCustom && td.TypeOrigin != api.ExternalCustom {
+		return
+	}
+
+	to := d.classOf(m, p, td.TypeDefinition)
+	if to == nil {
+		return
+	}
+
+	label := f.Name
+	if label == "" {
+		label = api.StereotypeEmbedded
+	}
+
+	d.Relations = append(d.Relations, &Relation{From: s.Name, To: to.ID, Kind: kind, Label: label})
+}
@@ -0,0 +1,55 @@
+package diagram
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+)
+
+func ExampleNewClassDiagram() {
+	// both packages are named store
+	a, b := "example.com/m/a/store", "example.com/m/b/store"
+	typ := func(path, name, src string, origin api.TypeOrigin) *api.TypeDesc {
+		td := api.NewTypeDesc(api.NewRefID(path, name), src, false, nil)
+		td.TypeOrigin = origin
+		return td
+	}
+	class := func(path, name string, st api.Stereotype, fields ...*api.Field) *api.Struct {
+		return &api.Struct{TypeDefinition: api.NewRefID(path, name), Name: name, Stereotypes: []api.Stereotype{st}, Fields: fields}
+	}
+
+	repo := class(a, "Repo", api.StereotypeStruct,
+		api.NewField("Items", "", "", typ(a, "Item", "[]Item", api.LocalCustom), nil),
+		api.NewField("Backup", "", "", typ(b, "Repo", "*store.Repo", api.ExternalCustom), nil),
+	)
+	repo.Implements = []api.RefId{api.NewRefID(a, "Finder")}
+
+	m := &api.Module{Name: "example.com/m", Packages: map[string]*api.Package{
+		a: {Name: "store", PackageDefinition: api.NewRefID(a, ""), Structs: map[string]*api.Struct{
+			"Repo":   repo,
+			"Item":   class(a, "Item", api.StereotypeStruct),
+			"Finder": class(a, "Finder", api.StereotypeInterface),
+		}},
+		b: {Name: "store", PackageDefinition: api.NewRefID(b, ""), Structs: map[string]*api.Struct{
+			"Repo": class(b, "Repo", api.StereotypeStruct),
+		}},
+	}}
+
+	d := NewClassDiagram(m, m.Packages[a])
+	fmt.Println(d.Name)
+	for _, c := range d.Classes {
+		fmt.Println(c.ID, c.QualifiedName(), c.Kind == External, c.Interface())
+	}
+	kinds := []string{Composition: "composition", Aggregation: "aggregation", Realization: "realization"}
+	for _, r := range d.Relations {
+		fmt.Println(r.From, r.To, kinds[r.Kind], r.Label)
+	}
+	// Output:
+	// classes-a-store
+	// Finder Finder false true
+	// Item Item false false
+	// Repo Repo false false
+	// example.com/m/b/store.Repo store.Repo true false
+	// Repo Item aggregation Items
+	// Repo example.com/m/b/store.Repo composition Backup
+	// Repo Finder realization
+}
@@ -0,0 +1,220 @@
+package diagram
+
+import (
+	"github.com/worldiety/gdoc/internal/api"
+	"sort"
+)
+
+const (
+	constructorGraphName = "constructors"
+	implementsLabel      = "implements"
+)
+
+// Wiring is a single step of the wiring order: the Type can be created by the Constructor, as soon as
+// all required types are available.
+type Wiring struct {
+	Type        api.RefId
+	Constructor api.RefId
+	Requires    []api.RefId
+	Cyclic      bool // the type depends on itself through other constructors and has no valid position
+}
+
+type constructorNode struct {
+	ref  api.RefId
+	node *Node
+}
+
+// NewConstructorGraph links the parameter types of all constructors (see api.Struct Constructors) to the type,
+// which they construct. Interfaces are linked to their implementations within the module, which are candidates to
+// satisfy them. Besides the graph, it returns the topologically sorted wiring order, which lists each constructor
+// after the constructors of all types it requires.
+func NewConstructorGraph(m *api.Module) (*Graph, []Wiring) {
+	g := &Graph{Header: Header{Name: constructorGraphName, Title: "Constructor dependencies"}}
+	refs := map[string]constructorNode{}
+	add := func(ref api.RefId, kind NodeKind, label, link string) string {
+		id := refKey(ref)
+		if _, ok := refs[id]; !ok {
+			refs[id] = constructorNode{ref: ref, node: g.AddNode(&Node{ID: id, Label: label, Kind: kind, Link: link})}
+		}
+		return id
+	}
+
+	type constructor struct {
+		s        *api.Struct
+		fn       *api.Function
+		requires []api.RefId
+	}
+
+	var constructors []constructor
+	for _, path := range sortedPaths(m) {
+		p := m.Packages[path]
+		for _, s := range sortedStructs(p) {
+			if len(s.Constructors) == 0 {
+				continue
+			}
+
+			to := add(s.TypeDefinition, Internal, p.Name+"."+s.Name, m.Anchor(s.TypeDefinition))
+			fns := append([]*api.Function{}, s.Constructors...)
+			sort.Slice(fns, func(i, j int) bool {
+				return fns[i].Name < fns[j].Name
+			})
+
+			for _, fn := range fns {
+				c := constructor{s: s, fn: fn}
+				for _, name := range sortedFieldNames(fn.Parameters) {
+					td := fn.Parameters[name].TypeDesc
+					if td.Map() && td.MapType != nil && td.MapType.ValueType != nil {
+						td = td.MapType.ValueType
+					}
+
+					var from string
+					switch td.TypeOrigin {
+					case api.LocalCustom, api.ExternalCustom:
+						other := m.Packages[td.TypeDefinition.ImportPath]
+						if other == nil {
+							continue
+						}
+						from = add(td.TypeDefinition, Internal, other.Name+"."+td.TypeDefinition.Identifier, m.Anchor(td.TypeDefinition))
+					case api.ExternalNonCustom:
+						// not declared in the module, thus the qualified name has no import path
+						qualified := td.PkgName() + "." + td.Identifier()
+						from = add(api.NewRefID("", qualified), External, qualified, "")
+					default:
+						continue
+					}
+
+					c.requires = appendRef(c.requires, refs[from].ref)
+					g.AddEdge(from, to, fn.Name)
+				}
+
+				constructors = append(constructors, c)
+			}
+		}
+	}
+
+	// interfaces, which are required by a constructor, may be satisfied by any implementation
+	for _, path := range sortedPaths(m) {
+		p := m.Packages[path]
+		for _, s := range sortedStructs(p) {
+			for _, iface := range s.Implements {
+				to := refKey(iface)
+				if _, ok := refs[to]; !ok {
+					continue
+				}
+
+				from := add(s.TypeDefinition, Internal, p.Name+"."+s.Name, m.Anchor(s.TypeDefinition))
+				g.AddEdge(from, to, implementsLabel)
+			}
+		}
+	}
+
+	cyclic := map[string]bool{}
+	for _, c := range g.HighlightCycles() {
+		for _, id := range c {
+			cyclic[id] = true
+		}
+	}
+
+	rank := map[string]int{}
+	for i, id := range topologicalOrder(g) {
+		rank[id] = i
+	}
+
+	sort.SliceStable(constructors, func(i, j int) bool {
+		return rank[refKey(constructors[i].s.TypeDefinition)] < rank[refKey(constructors[j].s.TypeDefinition)]
+	})
+
+	var wiring []Wiring
+	for _, c := range constructors {
+		wiring = append(wiring, Wiring{
+			Type:        c.s.TypeDefinition,
+			Constructor: c.fn.TypeDefinition,
+			Requires:    c.requires,
+			Cyclic:      cyclic[refKey(c.s.TypeDefinition)],
+		})
+	}
+
+	return g, wiring
+}
+
+func refKey(ref api.RefId) string {
+	return ref.ImportPath + "." + ref.Identifier
+}
+
+func appendRef(refs []api.RefId, ref api.RefId) []api.RefId {
+	for _, r := range refs {
+		if r == ref {
+			return refs
+		}
+	}
+	return append(refs, ref)
+}
+
+// topologicalOrder returns the node ids, so that each node follows all its predecessors, using Kahn's algorithm.
+// Nodes within cycles are appended at the end in their original order.
+func topologicalOrder(g *Graph) []string {
+	in := map[string]int{}
+	out := map[string][]string{}
+	for _, e := range g.Edges {
+		out[e.From] = append(out[e.From], e.To)
+		in[e.To]++
+	}
+
+	var queue, res []string
+	for _, n := range g.Nodes {
+		if in[n.ID] == 0 {
+			queue = append(queue, n.ID)
+		}
+	}
+
+	done := map[string]bool{}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		res = append(res, id)
+		done[id] = true
+		for _, to := range out[id] {
+			in[to]--
+			if in[to] == 0 {
+				queue = append(queue, to)
+			}
+		}
+	}
+
+	for _, n := range g.Nodes {
+		if !done[n.ID] {
+			res = append(res, n.ID)
+		}
+	}
+
+	return res
+}
+
+func sortedPaths(m *api.Module) []api.ImportPath {
+	paths := make([]api.ImportPath, 0, len(m.Packages))
+	for path := range m.Packages {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func sortedStructs(p *api.Package) []*api.Struct {
+	structs := make([]*api.Struct, 0, len(p.Structs))
+	for _, s := range p.Structs {
+		structs = append(structs, s)
+	}
+	sort.Slice(structs, func(i, j int) bool {
+		return structs[i].Name < structs[j].Name
+	})
+	return structs
+}
+
+func sortedFieldNames(fields map[string]*api.Field) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,65 @@
+package diagram
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+)
+
+func ExampleNewConstructorGraph() {
+	path := "example.com/m/store"
+	param := func(name, src string, origin api.TypeOrigin) *api.Field {
+		td := api.NewTypeDesc(api.NewRefID(path, name), src, false, nil)
+		td.TypeOrigin = origin
+		return api.NewField(name, "", "", td, nil)
+	}
+	class := func(name string, params ...*api.Field) *api.Struct {
+		s := &api.Struct{TypeDefinition: api.NewRefID(path, name), Name: name}
+		if len(params) > 0 {
+			fn := &api.Function{TypeDefinition: api.NewRefID(path, "New"+name), Name: "New" + name, Parameters: map[string]*api.Field{}}
+			for _, p := range params {
+				fn.Parameters[p.Name] = p
+			}
+			s.Constructors = []*api.Function{fn}
+		}
+		return s
+	}
+
+	// the Service requires the Repo, which requires any Finder like the DB
+	db := class("DB", param("Config", "Config", api.LocalCustom))
+	db.Implements = []api.RefId{api.NewRefID(path, "Finder")}
+	m := &api.Module{Name: "example.com/m", Packages: map[string]*api.Package{
+		path: {Name: "store", PackageDefinition: api.NewRefID(path, ""), Structs: map[string]*api.Struct{
+			"Config":  class("Config"),
+			"DB":      db,
+			"Finder":  class("Finder"),
+			"Repo":    class("Repo", param("Finder", "Finder", api.LocalCustom)),
+			"Service": class("Service", param("Repo", "*Repo", api.LocalCustom), param("Logger", "*log.Logger", api.ExternalNonCustom)),
+		}},
+	}}
+
+	g, wiring := NewConstructorGraph(m)
+	labels := map[string]string{}
+	for _, n := range g.Nodes {
+		labels[n.ID] = n.Label
+	}
+	for _, e := range g.Edges {
+		fmt.Println(labels[e.From], "->", labels[e.To], e.Label)
+	}
+
+	for _, w := range wiring {
+		var requires []string
+		for _, ref := range w.Requires {
+			requires = append(requires, ref.Identifier)
+		}
+		fmt.Println(w.Constructor.Identifier, requires, w.Cyclic)
+	}
+	// Output:
+	// store.Config -> store.DB NewDB
+	// store.Finder -> store.Repo NewRepo
+	// log.Logger -> store.Service NewService
+	// store.Repo -> store.Service NewService
+	// store.DB -> store.Finder implements
+	// NewDB [Config] false
+	// NewRepo [Finder] false
+	// NewService [log.Logger Repo] false
+}
@@ -0,0 +1,177 @@
+package diagram
+
+// NodeKind classifies a Node, so that renderers can style it accordingly.
+type NodeKind int
+
+const (
+	Internal NodeKind = iota // a package or type declared within the module
+	External                 // declared in a third party module
+	Stdlib                   // declared in the standard library
+)
+
+// Header contains the information, which all diagrams have in common.
+type Header struct {
+	Name  string // used as diagram and file name
+	Title string
+}
+
+func (h Header) Head() Header {
+	return h
+}
+
+// Diagram is implemented by all diagram models, like Graph and ClassDiagram.
+type Diagram interface {
+	Head() Header
+}
+
+// Graph is a directed graph, e.g. the import relations between the packages of a module.
+type Graph struct {
+	Header
+	Nodes []*Node
+	Edges []*Edge
+}
+
+type Node struct {
+	ID        string // unique within the graph
+	Label     string
+	Kind      NodeKind
+	Link      string // optional anchor id of the documented element
+	Highlight bool   // part of a cycle
+}
+
+type Edge struct {
+	From, To  string // node ids
+	Label     string
+	Highlight bool // part of a cycle
+}
+
+// Node returns the node with the given id or nil.
+func (g *Graph) Node(id string) *Node {
+	for _, n := range g.Nodes {
+		if n.ID == id {
+			return n
+		}
+	}
+
+	return nil
+}
+
+// AddNode inserts the node, if no other node with the same id exists and returns the contained node.
+func (g *Graph) AddNode(n *Node) *Node {
+	if o := g.Node(n.ID); o != nil {
+		return o
+	}
+
+	g.Nodes = append(g.Nodes, n)
+	return n
+}
+
+// AddEdge inserts a new edge, if it is neither a self reference nor a duplicate.
+func (g *Graph) AddEdge(from, to, label string) {
+	if from == to {
+		return
+	}
+
+	for _, e := range g.Edges {
+		if e.From == from && e.To == to {
+			return
+		}
+	}
+
+	g.Edges = append(g.Edges, &Edge{From: from, To: to, Label: label})
+}
+
+// HighlightCycles marks all nodes and edges which belong to a strongly connected component with more than one node.
+// It returns the found cycles.
+func (g *Graph) HighlightCycles() [][]string {
+	cycles := g.StronglyConnected()
+	member := map[string]int{}
+	for i, c := range cycles {
+		for _, id := range c {
+			member[id] = i
+		}
+	}
+
+	for _, n := range g.Nodes {
+		if _, ok := member[n.ID]; ok {
+			n.Highlight = true
+		}
+	}
+
+	for _, e := range g.Edges {
+		a, okA := member[e.From]
+		b, okB := member[e.To]
+		if okA && okB && a == b {
+			e.Highlight = true
+		}
+	}
+
+	return cycles
+}
+
+// StronglyConnected returns all strongly connected components containing more than one node, using Tarjan's algorithm.
+func (g *Graph) StronglyConnected() [][]string {
+	adj := map[string][]string{}
+	for _, e := range g.Edges {
+		adj[e.From] = append(adj[e.From], e.To)
+	}
+
+	var (
+		index   = 0
+		stack   []string
+		onStack = map[string]bool{}
+		indices = map[string]int{}
+		lowLink = map[string]int{}
+		res     [][]string
+	)
+
+	var connect func(v string)
+	connect = func(v string) {
+		indices[v] = index
+		lowLink[v] = index
+		index++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range adj[v] {
+			if _, visited := indices[w]; !visited {
+				connect(w)
+				lowLink[v] = min(lowLink[v], lowLink[w])
+			} else if onStack[w] {
+				lowLink[v] = min(lowLink[v], indices[w])
+			}
+		}
+
+		if lowLink[v] == indices[v] {
+			var component []string
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				component = append(component, w)
+				if w == v {
+					break
+				}
+			}
+
+			if len(component) > 1 {
+				res = append(res, component)
+			}
+		}
+	}
+
+	for _, n := range g.Nodes {
+		if _, visited := indices[n.ID]; !visited {
+			connect(n.ID)
+		}
+	}
+
+	return res
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,26 @@
+package diagram
+
+import "fmt"
+
+func ExampleGraph_HighlightCycles() {
+	g := &Graph{}
+	for _, id := range []string{"a", "b", "c", "d"} {
+		g.AddNode(&Node{ID: id, Label: id})
+	}
+
+	g.AddEdge("a", "b", "")
+	g.AddEdge("b", "c", "")
+	g.AddEdge("c", "a", "")
+	g.AddEdge("c", "d", "")
+
+	fmt.Println(len(g.HighlightCycles()))
+	for _, e := range g.Edges {
+		fmt.Println(e.From, e.To, e.Highlight)
+	}
+	// Output:
+	// 1
+	// a b true
+	// b c true
+	// c a true
+	// c d false
+}
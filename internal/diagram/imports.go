@@ -0,0 +1,79 @@
+package diagram
+
+import (
+	"github.com/worldiety/gdoc/internal/api"
+	"strings"
+)
+
+const importGraphName = "imports"
+
+// ImportGraphOptions control which packages are contained in the import graph.
+type ImportGraphOptions struct {
+	Collapse []string // packages below any of these import path prefixes are collapsed into a single node
+	Stdlib   bool     // include packages from the standard library
+	External bool     // include packages from other modules
+	Cycles   bool     // highlight import cycles
+}
+
+// NewImportGraph creates the package dependency graph of the given module from the collected api.Package Imports.
+func NewImportGraph(m *api.Module, opts ImportGraphOptions) *Graph {
+	g := &Graph{Header: Header{Name: importGraphName, Title: "Package dependencies"}}
+
+	for _, path := range sortedPaths(m) {
+		p := m.Packages[path]
+		from := g.AddNode(newImportNode(m, path, opts))
+		for _, imp := range p.Imports {
+			kind := importKind(m, string(imp))
+			if (kind == Stdlib && !opts.Stdlib) || (kind == External && !opts.External) {
+				continue
+			}
+
+			to := g.AddNode(newImportNode(m, string(imp), opts))
+			g.AddEdge(from.ID, to.ID, "")
+		}
+	}
+
+	if opts.Cycles {
+		g.HighlightCycles()
+	}
+
+	return g
+}
+
+func newImportNode(m *api.Module, path string, opts ImportGraphOptions) *Node {
+	id := collapse(path, opts.Collapse)
+	n := &Node{
+		ID:    id,
+		Label: strings.TrimPrefix(id, m.Name+"/"),
+		Kind:  importKind(m, path),
+	}
+
+	if p, ok := m.Packages[id]; ok {
+		n.Link = p.Name
+	}
+
+	return n
+}
+
+func collapse(path string, prefixes []string) string {
+	for _, prefix := range prefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return prefix
+		}
+	}
+
+	return path
+}
+
+func importKind(m *api.Module, path string) NodeKind {
+	if _, ok := m.Packages[path]; ok || path == m.Name || strings.HasPrefix(path, m.Name+"/") {
+		return Internal
+	}
+
+	// by convention, only the standard library omits a domain name in the first path element
+	if !strings.Contains(strings.Split(path, "/")[0], ".") {
+		return Stdlib
+	}
+
+	return External
+}
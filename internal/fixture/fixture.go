@@ -0,0 +1,82 @@
+// Package fixture provides the example module of the testdata directory to the tests of the other packages, so
+// that they check the behavior against real parsed sources instead of hand built models.
+package fixture
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+)
+
+// Name is the module path of the example module.
+const Name = "example.com/m"
+
+// Dir returns the root directory of the example module.
+func Dir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "testdata", "example")
+}
+
+// Parse parses the example module without resolving it.
+func Parse() (*api.Module, error) {
+	m, err := golang.Parse(Dir())
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse example module: %w", err)
+	}
+
+	return m, nil
+}
+
+// Copy copies the example module into dir, e.g. to modify it or to commit it into a git repository.
+func Copy(dir string) error {
+	return filepath.Walk(Dir(), func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(Dir(), path)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return os.MkdirAll(filepath.Join(dir, rel), 0755)
+		}
+
+		buf, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("cannot copy example module: %w", err)
+		}
+
+		return os.WriteFile(filepath.Join(dir, rel), buf, 0644)
+	})
+}
+
+// Resolve parses and resolves the example module and links its comments with the default anchors. The packages are
+// type checked from source, so that the result does not depend on the export data format of the installed go version.
+func Resolve() (*api.Module, error) {
+	m, err := Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for path := range m.Packages {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	pkgs, err := golang.NewChecker(Dir(), Name).Packages(paths...)
+	if err != nil {
+		return nil, err
+	}
+
+	golang.ResolvePackages(m, pkgs)
+	golang.LinkComments(m)
+	return m, nil
+}
@@ -0,0 +1,39 @@
+// Package asciidoc renders a golang.AModule as a single asciidoc document using text templates.
+//
+// The entry point is the template "document", which executes the other templates in the following order. Each
+// template receives the listed data as dot:
+//
+//	document      golang.AModule, renders the whole module
+//	header        golang.AsciiDocHeader, the document attributes
+//	module        golang.AModule, the module title, readme, diagrams and wiring order
+//	diagram       golang.ADiagram, a module or package diagram
+//	wiring        golang.AWiringOrder, the table of the constructors in calling order
+//	package       golang.APackage, the package title, readme and diagrams
+//	constants     golang.AConstBlockList, use Blocks and AConsts for the sorted constants
+//	const         golang.AConst, a single constant line
+//	variables     golang.AVariables, use Plain and Documented
+//	var           golang.AVariable, a single variable line
+//	structs       golang.AStructs, the structs and interfaces of a package
+//	struct        golang.AStruct, use AConstructors and AMethods
+//	references    golang.AStruct, the declarations which refer to the struct
+//	functions     golang.AFunctions, the functions of a package, which are no constructors
+//	function      golang.AFunction
+//	method        golang.AMethod
+//	calls         golang.AFunction or golang.AMethod, the calls from and to the function
+//	appendix      golang.AAppendix
+//	index         golang.AIndex, use Groups
+//	deprecations  golang.ADeprecations
+//	glossary      golang.AGlossary
+//
+// The templates format fragments with the helpers of golang.Funcs, most notably:
+//
+//	link          cross reference to a declaration or package, qualified if the optional context is another package
+//	anchor        anchor of a declaration or package
+//	codeBlock     a [.code] block
+//	typeString    the linked type of a field, variable or type description
+//	comment       a doc comment converted into asciidoc
+//	signature     the highlighted signature of a function or method
+//	declaration   the highlighted type declaration of a struct or interface
+//
+// See NewTemplates for overriding individual templates.
+package asciidoc
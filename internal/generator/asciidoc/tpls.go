@@ -3,7 +3,9 @@ package asciidoc
 import (
 	"embed"
 	"fmt"
-	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+	"os"
+	"path/filepath"
 	"text/template"
 )
 
@@ -12,15 +14,47 @@ var templateFiles embed.FS
 
 // Templates is globally available and binds all template files in the asciidoc/templates directory.
 // All available templates have to be called by name, to use them.
-var Templates *template.Template // test comment
-var TestVar *api.Stereotype
-var TestVar2 api.Stereotype
+var Templates *template.Template
 
 func init() {
-	tpl, err := template.ParseFS(templateFiles, "templates/*.tmpl")
+	tpl, err := NewTemplates("")
 	if err != nil {
-		panic(fmt.Errorf("cannot parse embedded templates: %w", err))
+		panic(err)
 	}
 
 	Templates = tpl
 }
+
+// NewTemplates parses the embedded templates and afterwards all *.tmpl files of the given directory, if not empty.
+// A template of the directory replaces the embedded template of the same name, so that a custom layout only has to
+// define the templates it changes. See the package documentation for the names and the data of each template.
+func NewTemplates(dir string) (*template.Template, error) {
+	tpl, err := template.New("").Funcs(golang.Funcs(nil)).ParseFS(templateFiles, "templates/*.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse embedded templates: %w", err)
+	}
+
+	if dir == "" {
+		return tpl, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot list templates: %w", err)
+	}
+
+	if len(files) == 0 {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("cannot read templates: %w", err)
+		}
+
+		return tpl, nil
+	}
+
+	tpl, err = tpl.ParseFiles(files...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse templates of %s: %w", dir, err)
+	}
+
+	return tpl, nil
+}
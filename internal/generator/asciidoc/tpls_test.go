@@ -0,0 +1,96 @@
+package asciidoc
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/fixture"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func ExampleNewTemplates() {
+	dir, err := os.MkdirTemp("", "templates")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	layout := `{{ define "module" }}= {{ .Name }}
+{{ end }}`
+	if err := os.WriteFile(filepath.Join(dir, "module.tmpl"), []byte(layout), 0644); err != nil {
+		panic(err)
+	}
+
+	tpl, err := NewTemplates(dir)
+	if err != nil {
+		panic(err)
+	}
+
+	m, err := fixture.Parse()
+	if err != nil {
+		panic(err)
+	}
+
+	buf, err := CreateModuleTemplate(tpl, golang.NewAModule(*m))
+	if err != nil {
+		panic(err)
+	}
+
+	doc, _, _ := strings.Cut(buf.String(), "== [keyword]#Package#")
+	fmt.Println(strings.TrimSpace(doc))
+	// Output:
+	// :docinfo: shared
+	// :toc:
+	// = example.com/m
+}
+
+func ExampleCreateModuleTemplate() {
+	m, err := fixture.Resolve()
+	if err != nil {
+		panic(err)
+	}
+	m.Anchors = api.NewAnchors(api.ReadableAnchors{Module: m.Name}, m)
+
+	buf, err := CreateModuleTemplate(Templates, golang.NewAModule(*m))
+	if err != nil {
+		panic(err)
+	}
+
+	// the variables and the references of Mode, quoted to show the blank lines and the trailing white space
+	_, doc, _ := strings.Cut(buf.String(), "== [keyword]#Package# [[store]]")
+	_, vars, _ := strings.Cut(doc, "=== Variables")
+	vars, _, _ = strings.Cut(vars, "=== Structs")
+	_, refs, _ := strings.Cut(doc, "**Referenced by:**")
+	refs, _, _ = strings.Cut(refs, "'''")
+	for _, line := range strings.Split(vars+"**Referenced by:**"+refs, "\n") {
+		fmt.Printf("%q\n", line)
+	}
+	// Output:
+	// " "
+	// "[.code]"
+	// "****"
+	// "[type]#var# [[store--Strict]][variable]#Strict# <<store--Mode, [type]#Mode#>> +"
+	// ""
+	// "****"
+	// ""
+	// ""
+	// "[.code]"
+	// "****"
+	// "DefaultMode is used, if no mode is given."
+	// " +"
+	// "[builtin]#var# [[store--DefaultMode]][variable]#DefaultMode# <<store--Mode, [type]#Mode#>> pass:[//] "
+	// "****"
+	// ""
+	// ""
+	// "**Referenced by:**"
+	// ""
+	// "* <<store--DefaultMode, DefaultMode>> __variable__"
+	// "* <<store--NewStore, NewStore>> __parameter__ [variable]#mode#"
+	// "* <<store--Store, Store>> __property__ [variable]#Mode#"
+	// "* <<store--Strict, Strict>> __variable__"
+	// ""
+	// ""
+	// ""
+}
@@ -0,0 +1,62 @@
+// Package badge renders status badges in the flat style of shields.io as standalone svg images.
+package badge
+
+import (
+	"fmt"
+	"html"
+)
+
+const (
+	charWidth = 6.5 // the approximate advance of Verdana at 11px
+	padding   = 6.0
+	height    = 20.0
+)
+
+// Colors of the message part.
+const (
+	Red    = "#e05d44"
+	Orange = "#fe7d37"
+	Yellow = "#dfb317"
+	Green  = "#97ca00"
+	Bright = "#4c1"
+)
+
+// Percent returns the color for a percentage, which is red below 50 and bright green from 90 upwards.
+func Percent(p float64) string {
+	switch {
+	case p >= 90:
+		return Bright
+	case p >= 75:
+		return Green
+	case p >= 60:
+		return Yellow
+	case p >= 50:
+		return Orange
+	default:
+		return Red
+	}
+}
+
+// Svg renders a badge with a grey label and a colored message.
+func Svg(label, message, color string) string {
+	lw := width(label)
+	mw := width(message)
+	w := lw + mw
+	label, message = html.EscapeString(label), html.EscapeString(message)
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]g" height="%[2]g" role="img" aria-label="%[3]s: %[4]s">
+<title>%[3]s: %[4]s</title>
+<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="%[1]g" height="%[2]g" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)"><rect width="%[5]g" height="%[2]g" fill="#555"/><rect x="%[5]g" width="%[6]g" height="%[2]g" fill="%[7]s"/><rect width="%[1]g" height="%[2]g" fill="url(#s)"/></g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="%[8]g" y="15" fill="#010101" fill-opacity=".3">%[3]s</text><text x="%[8]g" y="14">%[3]s</text>
+<text x="%[9]g" y="15" fill="#010101" fill-opacity=".3">%[4]s</text><text x="%[9]g" y="14">%[4]s</text>
+</g>
+</svg>
+`, w, height, label, message, lw, mw, color, lw/2, lw+mw/2)
+}
+
+func width(s string) float64 {
+	return float64(len([]rune(s)))*charWidth + 2*padding
+}
@@ -0,0 +1,102 @@
+// Package changelog renders an apidiff.Report as release notes.
+package changelog
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/apidiff"
+	"strings"
+)
+
+var kinds = []apidiff.Kind{apidiff.Added, apidiff.Removed, apidiff.Changed}
+
+var kindTitles = map[apidiff.Kind]string{
+	apidiff.Added:   "Added",
+	apidiff.Removed: "Removed",
+	apidiff.Changed: "Changed",
+}
+
+// markup abstracts the few elements, which differ between asciidoc and markdown.
+type markup struct {
+	heading func(level int, s string) string
+	code    func(s string) string
+}
+
+var asciidoc = markup{
+	heading: func(level int, s string) string { return strings.Repeat("=", level) + " " + s },
+	code:    func(s string) string { return "`+" + s + "+`" },
+}
+
+var markdown = markup{
+	heading: func(level int, s string) string { return strings.Repeat("#", level) + " " + s },
+	code: func(s string) string {
+		if strings.Contains(s, "`") {
+			return "`` " + s + " ``"
+		}
+		return "`" + s + "`"
+	},
+}
+
+// Asciidoc renders the report as an asciidoc document with a section per package.
+func Asciidoc(r *apidiff.Report) string {
+	return render(r, asciidoc)
+}
+
+// Markdown renders the report as a markdown document with a section per package.
+func Markdown(r *apidiff.Report) string {
+	return render(r, markdown)
+}
+
+func render(r *apidiff.Report, m markup) string {
+	var sb strings.Builder
+	sb.WriteString(m.heading(1, fmt.Sprintf("API changes of %s from %s to %s", r.Module, r.Old, r.New)))
+	sb.WriteString("\n\n")
+
+	if len(r.Changes) == 0 {
+		sb.WriteString("There are no changes of the exported API.\n")
+		return sb.String()
+	}
+
+	for _, path := range r.Packages() {
+		sb.WriteString(m.heading(2, "Package "+m.code(path)))
+		sb.WriteString("\n\n")
+
+		for _, kind := range kinds {
+			var lines []string
+			for _, c := range r.Changes {
+				if c.Package != path || c.Kind != kind {
+					continue
+				}
+
+				// fields are not qualified by their declaration
+				var prefix string
+				if c.Symbol == api.SymbolField || kind == apidiff.Changed {
+					prefix = c.Name + ": "
+					if c.Name == "" {
+						prefix = string(c.Symbol) + ": "
+					}
+				}
+
+				switch kind {
+				case apidiff.Added:
+					lines = append(lines, "* "+prefix+m.code(c.New))
+				case apidiff.Removed:
+					lines = append(lines, "* "+prefix+m.code(c.Old))
+				case apidiff.Changed:
+					lines = append(lines, fmt.Sprintf("* %s%s → %s", prefix, m.code(c.Old), m.code(c.New)))
+				}
+			}
+
+			if len(lines) == 0 {
+				continue
+			}
+
+			sb.WriteString(m.heading(3, kindTitles[kind]))
+			sb.WriteString("\n\n")
+			sb.WriteString(strings.Join(lines, "\n"))
+			sb.WriteString("\n\n")
+		}
+	}
+
+	return sb.String()
+}
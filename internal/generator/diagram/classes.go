@@ -0,0 +1,211 @@
+package diagram
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/diagram"
+	"strconv"
+	"strings"
+)
+
+func classLabel(c *diagram.Class, open, close string) string {
+	s := c.QualifiedName()
+	if len(c.Generics) > 0 {
+		s += open + strings.Join(c.Generics, ", ") + close
+	}
+	return s
+}
+
+// classIDs maps the class ids to identifiers, which are valid in all diagram languages.
+func classIDs(d *diagram.ClassDiagram) map[string]string {
+	ids := map[string]string{}
+	for i, c := range d.Classes {
+		ids[c.ID] = "c" + strconv.Itoa(i)
+	}
+	return ids
+}
+
+func dotClasses(d *diagram.ClassDiagram) string {
+	ids := classIDs(d)
+	var sb strings.Builder
+	sb.WriteString("digraph " + quote(d.Name) + " {\n")
+	sb.WriteString("  rankdir=BT;\n")
+	sb.WriteString("  node [shape=record, style=filled, fillcolor=\"#ffffff\", fontname=\"Helvetica\"];\n")
+	for _, c := range d.Classes {
+		name := classLabel(c, "[", "]")
+		if c.Interface() {
+			name = "«interface»\\n" + name
+		}
+
+		label := "{" + dotRecordEscape(name)
+		if c.Kind == diagram.Internal {
+			label += "|" + dotRecordLines(c.Fields) + "|" + dotRecordLines(c.Methods)
+		}
+		label += "}"
+
+		attrs := []string{"label=\"" + label + "\""}
+		if c.Kind != diagram.Internal {
+			attrs = append(attrs, "fillcolor=\"#eeeeee\"")
+		}
+
+		sb.WriteString(fmt.Sprintf("  %s [%s];\n", ids[c.ID], strings.Join(attrs, ", ")))
+	}
+
+	for _, r := range d.Relations {
+		var attrs []string
+		switch r.Kind {
+		case diagram.Composition:
+			attrs = append(attrs, "dir=back", "arrowtail=diamond")
+		case diagram.Aggregation:
+			attrs = append(attrs, "dir=back", "arrowtail=odiamond")
+		case diagram.Realization:
+			attrs = append(attrs, "style=dashed", "arrowhead=empty")
+		}
+
+		if r.Label != "" {
+			attrs = append(attrs, "label="+quote(r.Label))
+		}
+
+		// whole-part relations point from the part to the whole, so that the diamond is drawn at the whole
+		from, to := r.From, r.To
+		if r.Kind != diagram.Realization {
+			from, to = to, from
+		}
+
+		sb.WriteString(fmt.Sprintf("  %s -> %s [%s];\n", ids[from], ids[to], strings.Join(attrs, ", ")))
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func dotRecordLines(lines []string) string {
+	var sb strings.Builder
+	for _, l := range lines {
+		sb.WriteString(dotRecordEscape(l) + "\\l")
+	}
+	return sb.String()
+}
+
+func dotRecordEscape(s string) string {
+	r := strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "{", "\\{", "}", "\\}", "|", "\\|", "<", "\\<", ">", "\\>")
+	return r.Replace(s)
+}
+
+func plantUMLClasses(d *diagram.ClassDiagram) string {
+	ids := classIDs(d)
+	var sb strings.Builder
+	sb.WriteString("@startuml\n")
+	sb.WriteString("hide empty members\n")
+	for _, c := range d.Classes {
+		kind := "class"
+		if c.Interface() {
+			kind = "interface"
+		}
+
+		sb.WriteString(fmt.Sprintf("%s %s as %s", kind, quote(classLabel(c, "<", ">")), ids[c.ID]))
+		if c.Kind != diagram.Internal {
+			sb.WriteString(" #eeeeee\n")
+			continue
+		}
+
+		sb.WriteString(" {\n")
+		for _, f := range c.Fields {
+			sb.WriteString("  +" + f + "\n")
+		}
+		for _, m := range c.Methods {
+			sb.WriteString("  +" + m + "\n")
+		}
+		sb.WriteString("}\n")
+	}
+
+	for _, r := range d.Relations {
+		var arrow string
+		switch r.Kind {
+		case diagram.Composition:
+			arrow = "*--"
+		case diagram.Aggregation:
+			arrow = "o--"
+		case diagram.Realization:
+			arrow = "..|>"
+		}
+
+		// whole-part relations are written from the whole to the part
+		sb.WriteString(fmt.Sprintf("%s %s %s", ids[r.From], arrow, ids[r.To]))
+		if r.Label != "" {
+			sb.WriteString(" : " + r.Label)
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("@enduml\n")
+	return sb.String()
+}
+
+func mermaidClasses(d *diagram.ClassDiagram) string {
+	ids := classIDs(d)
+	var sb strings.Builder
+	sb.WriteString("classDiagram\n")
+	for _, c := range d.Classes {
+		sb.WriteString(fmt.Sprintf("  class %s[%s]", ids[c.ID], quote(classLabel(c, "~", "~"))))
+		if c.Kind != diagram.Internal {
+			sb.WriteString("\n")
+			continue
+		}
+
+		sb.WriteString(" {\n")
+		if c.Interface() {
+			sb.WriteString("    <<interface>>\n")
+		}
+		for _, f := range c.Fields {
+			sb.WriteString("    +" + mermaidMember(f) + "\n")
+		}
+		for _, m := range c.Methods {
+			sb.WriteString("    +" + mermaidMember(m) + "\n")
+		}
+		sb.WriteString("  }\n")
+	}
+
+	for _, r := range d.Relations {
+		var arrow string
+		switch r.Kind {
+		case diagram.Composition:
+			arrow = "*--"
+		case diagram.Aggregation:
+			arrow = "o--"
+		case diagram.Realization:
+			arrow = "..|>"
+		}
+
+		sb.WriteString(fmt.Sprintf("  %s %s %s", ids[r.From], arrow, ids[r.To]))
+		if r.Label != "" {
+			sb.WriteString(" : " + r.Label)
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// mermaidMember replaces the square brackets of generics by the tilde notation, which mermaid expects.
+// Brackets of slices and arrays are kept.
+func mermaidMember(s string) string {
+	var sb strings.Builder
+	var generic []bool
+	for i, r := range s {
+		switch {
+		case r == '[':
+			isGeneric := i+1 < len(s) && s[i+1] != ']' && (s[i+1] < '0' || s[i+1] > '9')
+			generic = append(generic, isGeneric)
+			if isGeneric {
+				r = '~'
+			}
+		case r == ']' && len(generic) > 0:
+			if generic[len(generic)-1] {
+				r = '~'
+			}
+			generic = generic[:len(generic)-1]
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,221 @@
+package diagram
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/diagram"
+	"strconv"
+	"strings"
+)
+
+// Format is a textual diagram language.
+type Format string
+
+const (
+	Dot      Format = "dot"
+	PlantUML Format = "plantuml"
+	Mermaid  Format = "mermaid"
+	Svg      Format = "svg" // rendered by the built-in layout engine, without any external tools
+)
+
+const highlightColor = "#d62728"
+
+// ParseFormat returns the Format for the given name, which may be empty to disable diagrams.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case "", Dot, PlantUML, Mermaid, Svg:
+		return f, nil
+	default:
+		return "", fmt.Errorf("invalid diagram format: %s", s)
+	}
+}
+
+// Ext returns the usual file extension for a standalone file.
+func (f Format) Ext() string {
+	switch f {
+	case Dot:
+		return ".dot"
+	case PlantUML:
+		return ".puml"
+	case Mermaid:
+		return ".mmd"
+	case Svg:
+		return ".svg"
+	default:
+		return ""
+	}
+}
+
+// Block returns the block style name as expected by asciidoctor-diagram.
+// Svg diagrams are already rendered and need no extension.
+func (f Format) Block() string {
+	switch f {
+	case Dot:
+		return "graphviz"
+	case Svg:
+		return ""
+	default:
+		return string(f)
+	}
+}
+
+// Render returns the source of the given diagram in the according diagram language.
+func Render(f Format, d diagram.Diagram) (string, error) {
+	switch t := d.(type) {
+	case *diagram.Graph:
+		switch f {
+		case Dot:
+			return dotGraph(t), nil
+		case PlantUML:
+			return plantUMLGraph(t), nil
+		case Mermaid:
+			return mermaidGraph(t), nil
+		case Svg:
+			return svgGraph(t), nil
+		}
+	case *diagram.ClassDiagram:
+		switch f {
+		case Dot:
+			return dotClasses(t), nil
+		case PlantUML:
+			return plantUMLClasses(t), nil
+		case Mermaid:
+			return mermaidClasses(t), nil
+		case Svg:
+			return svgClasses(t), nil
+		}
+	default:
+		return "", fmt.Errorf("unsupported diagram type %T", d)
+	}
+
+	return "", fmt.Errorf("invalid diagram format: %s", f)
+}
+
+// nodeIDs maps the arbitrary node ids to identifiers, which are valid in all diagram languages.
+func nodeIDs(g *diagram.Graph) map[string]string {
+	ids := map[string]string{}
+	for i, n := range g.Nodes {
+		ids[n.ID] = "n" + strconv.Itoa(i)
+	}
+	return ids
+}
+
+func quote(s string) string {
+	return strconv.Quote(s)
+}
+
+func dotGraph(g *diagram.Graph) string {
+	ids := nodeIDs(g)
+	var sb strings.Builder
+	sb.WriteString("digraph " + quote(g.Name) + " {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=\"#ffffff\", fontname=\"Helvetica\"];\n")
+	for _, n := range g.Nodes {
+		attrs := []string{"label=" + quote(n.Label)}
+		switch n.Kind {
+		case diagram.External:
+			attrs = append(attrs, "fillcolor=\"#eeeeee\"")
+		case diagram.Stdlib:
+			attrs = append(attrs, "fillcolor=\"#e8f0fe\"")
+		}
+
+		if n.Highlight {
+			attrs = append(attrs, "color="+quote(highlightColor))
+		}
+
+		sb.WriteString(fmt.Sprintf("  %s [%s];\n", ids[n.ID], strings.Join(attrs, ", ")))
+	}
+
+	for _, e := range g.Edges {
+		var attrs []string
+		if e.Label != "" {
+			attrs = append(attrs, "label="+quote(e.Label))
+		}
+
+		if e.Highlight {
+			attrs = append(attrs, "color="+quote(highlightColor), "penwidth=2")
+		}
+
+		sb.WriteString(fmt.Sprintf("  %s -> %s", ids[e.From], ids[e.To]))
+		if len(attrs) > 0 {
+			sb.WriteString(" [" + strings.Join(attrs, ", ") + "]")
+		}
+		sb.WriteString(";\n")
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func plantUMLGraph(g *diagram.Graph) string {
+	ids := nodeIDs(g)
+	var sb strings.Builder
+	sb.WriteString("@startuml\n")
+	sb.WriteString("left to right direction\n")
+	for _, n := range g.Nodes {
+		var stereotype, color string
+		switch n.Kind {
+		case diagram.External:
+			stereotype = " <<external>>"
+		case diagram.Stdlib:
+			stereotype = " <<stdlib>>"
+		}
+
+		if n.Highlight {
+			color = " " + highlightColor
+		}
+
+		sb.WriteString(fmt.Sprintf("rectangle %s as %s%s%s\n", quote(n.Label), ids[n.ID], stereotype, color))
+	}
+
+	for _, e := range g.Edges {
+		arrow := "-->"
+		if e.Highlight {
+			arrow = "-[" + highlightColor + ",bold]->"
+		}
+
+		sb.WriteString(fmt.Sprintf("%s %s %s", ids[e.From], arrow, ids[e.To]))
+		if e.Label != "" {
+			sb.WriteString(" : " + e.Label)
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("@enduml\n")
+	return sb.String()
+}
+
+func mermaidGraph(g *diagram.Graph) string {
+	ids := nodeIDs(g)
+	var sb strings.Builder
+	sb.WriteString("graph LR\n")
+	sb.WriteString("  classDef external fill:#eeeeee\n")
+	sb.WriteString("  classDef stdlib fill:#e8f0fe\n")
+	sb.WriteString("  classDef cycle stroke:" + highlightColor + ",stroke-width:2px\n")
+	for _, n := range g.Nodes {
+		sb.WriteString(fmt.Sprintf("  %s[%s]\n", ids[n.ID], quote(n.Label)))
+		switch n.Kind {
+		case diagram.External:
+			sb.WriteString(fmt.Sprintf("  class %s external\n", ids[n.ID]))
+		case diagram.Stdlib:
+			sb.WriteString(fmt.Sprintf("  class %s stdlib\n", ids[n.ID]))
+		}
+
+		if n.Highlight {
+			sb.WriteString(fmt.Sprintf("  class %s cycle\n", ids[n.ID]))
+		}
+	}
+
+	for i, e := range g.Edges {
+		if e.Label != "" {
+			sb.WriteString(fmt.Sprintf("  %s -->|%s| %s\n", ids[e.From], quote(e.Label), ids[e.To]))
+		} else {
+			sb.WriteString(fmt.Sprintf("  %s --> %s\n", ids[e.From], ids[e.To]))
+		}
+
+		if e.Highlight {
+			sb.WriteString(fmt.Sprintf("  linkStyle %d stroke:%s,stroke-width:2px\n", i, highlightColor))
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,270 @@
+package diagram
+
+import (
+	"github.com/worldiety/gdoc/internal/diagram"
+	"sort"
+)
+
+// barycenterSweeps is the amount of down and up sweeps used to reduce edge crossings.
+const barycenterSweeps = 8
+
+// layeredNode is either a node of the graph or a dummy node, which routes an edge across a layer.
+type layeredNode struct {
+	id     string
+	node   *diagram.Node // nil for dummies
+	layer  int
+	order  int
+	x, y   float64
+	width  float64
+	height float64
+}
+
+// layeredEdge connects two nodes of adjacent layers.
+type layeredEdge struct {
+	from, to *layeredNode
+}
+
+// layeredRoute is the path of an original edge through its dummy nodes.
+type layeredRoute struct {
+	edge     *diagram.Edge
+	points   []*layeredNode
+	reversed bool // the route has been reversed to break a cycle
+}
+
+// layeredLayout is a Sugiyama style layout: cycles are broken, nodes are assigned to layers by their longest
+// path, long edges are split by dummy nodes, crossings are reduced by the barycenter heuristic and finally
+// coordinates are assigned. Layers are arranged from left to right.
+type layeredLayout struct {
+	layers [][]*layeredNode
+	routes []*layeredRoute
+	width  float64
+	height float64
+}
+
+func newLayeredLayout(g *diagram.Graph, size func(n *diagram.Node) (float64, float64)) *layeredLayout {
+	l := &layeredLayout{}
+	nodes := map[string]*layeredNode{}
+	for _, n := range g.Nodes {
+		w, h := size(n)
+		nodes[n.ID] = &layeredNode{id: n.ID, node: n, width: w, height: h}
+	}
+
+	reversed := acyclicReversals(g)
+	assignLayers(g, nodes, reversed)
+
+	var edges []*layeredEdge
+	for i, e := range g.Edges {
+		from, to := nodes[e.From], nodes[e.To]
+		if from == nil || to == nil {
+			continue
+		}
+
+		route := &layeredRoute{edge: e, reversed: reversed[i]}
+		if route.reversed {
+			from, to = to, from
+		}
+
+		route.points = append(route.points, from)
+		prev := from
+		for layer := from.layer + 1; layer < to.layer; layer++ {
+			dummy := &layeredNode{id: e.From + "->" + e.To, layer: layer}
+			edges = append(edges, &layeredEdge{from: prev, to: dummy})
+			route.points = append(route.points, dummy)
+			prev = dummy
+		}
+		edges = append(edges, &layeredEdge{from: prev, to: to})
+		route.points = append(route.points, to)
+		l.routes = append(l.routes, route)
+	}
+
+	// collect all distinct nodes in a stable order, the graph nodes first
+	seen := map[*layeredNode]bool{}
+	var all []*layeredNode
+	for _, n := range g.Nodes {
+		all = append(all, nodes[n.ID])
+		seen[nodes[n.ID]] = true
+	}
+	for _, r := range l.routes {
+		for _, p := range r.points {
+			if !seen[p] {
+				seen[p] = true
+				all = append(all, p)
+			}
+		}
+	}
+
+	for _, n := range all {
+		for len(l.layers) <= n.layer {
+			l.layers = append(l.layers, nil)
+		}
+		n.order = len(l.layers[n.layer])
+		l.layers[n.layer] = append(l.layers[n.layer], n)
+	}
+
+	l.reduceCrossings(edges)
+	return l
+}
+
+// acyclicReversals returns the indices of all edges, which must be reversed to make the graph acyclic.
+// These are the back edges of a depth first search.
+func acyclicReversals(g *diagram.Graph) map[int]bool {
+	out := map[string][]int{}
+	for i, e := range g.Edges {
+		out[e.From] = append(out[e.From], i)
+	}
+
+	const (
+		unvisited = iota
+		active
+		done
+	)
+
+	state := map[string]int{}
+	res := map[int]bool{}
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = active
+		for _, i := range out[id] {
+			to := g.Edges[i].To
+			switch state[to] {
+			case unvisited:
+				visit(to)
+			case active:
+				res[i] = true
+			}
+		}
+		state[id] = done
+	}
+
+	for _, n := range g.Nodes {
+		if state[n.ID] == unvisited {
+			visit(n.ID)
+		}
+	}
+
+	return res
+}
+
+// assignLayers puts each node into the layer after its longest path from any source.
+func assignLayers(g *diagram.Graph, nodes map[string]*layeredNode, reversed map[int]bool) {
+	in := map[string]int{}
+	out := map[string][]string{}
+	for i, e := range g.Edges {
+		from, to := e.From, e.To
+		if reversed[i] {
+			from, to = to, from
+		}
+		out[from] = append(out[from], to)
+		in[to]++
+	}
+
+	var queue []string
+	for _, n := range g.Nodes {
+		if in[n.ID] == 0 {
+			queue = append(queue, n.ID)
+		}
+	}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, to := range out[id] {
+			if nodes[id].layer+1 > nodes[to].layer {
+				nodes[to].layer = nodes[id].layer + 1
+			}
+
+			in[to]--
+			if in[to] == 0 {
+				queue = append(queue, to)
+			}
+		}
+	}
+}
+
+// reduceCrossings reorders the nodes within their layers by the barycenter of their neighbours.
+func (l *layeredLayout) reduceCrossings(edges []*layeredEdge) {
+	preds := map[*layeredNode][]*layeredNode{}
+	succs := map[*layeredNode][]*layeredNode{}
+	for _, e := range edges {
+		preds[e.to] = append(preds[e.to], e.from)
+		succs[e.from] = append(succs[e.from], e.to)
+	}
+
+	for i := 0; i < barycenterSweeps; i++ {
+		if i%2 == 0 {
+			for layer := 1; layer < len(l.layers); layer++ {
+				l.sortByBarycenter(layer, preds)
+			}
+		} else {
+			for layer := len(l.layers) - 2; layer >= 0; layer-- {
+				l.sortByBarycenter(layer, succs)
+			}
+		}
+	}
+}
+
+func (l *layeredLayout) sortByBarycenter(layer int, neighbours map[*layeredNode][]*layeredNode) {
+	nodes := l.layers[layer]
+	center := map[*layeredNode]float64{}
+	for _, n := range nodes {
+		adj := neighbours[n]
+		if len(adj) == 0 {
+			center[n] = float64(n.order)
+			continue
+		}
+
+		var sum float64
+		for _, a := range adj {
+			sum += float64(a.order)
+		}
+		center[n] = sum / float64(len(adj))
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return center[nodes[i]] < center[nodes[j]]
+	})
+
+	for i, n := range nodes {
+		n.order = i
+	}
+}
+
+// place assigns the coordinates, using the given gaps between layers and nodes.
+func (l *layeredLayout) place(margin, layerGap, nodeGap float64) {
+	var heights []float64
+	for _, layer := range l.layers {
+		var h float64
+		for i, n := range layer {
+			if i > 0 {
+				h += nodeGap
+			}
+			h += n.height
+		}
+		heights = append(heights, h)
+		if h > l.height {
+			l.height = h
+		}
+	}
+
+	x := margin
+	for i, layer := range l.layers {
+		var w float64
+		for _, n := range layer {
+			if n.width > w {
+				w = n.width
+			}
+		}
+
+		y := margin + (l.height-heights[i])/2
+		for _, n := range layer {
+			n.x = x + (w-n.width)/2
+			n.y = y
+			y += n.height + nodeGap
+		}
+
+		x += w + layerGap
+	}
+
+	l.width = x - layerGap + margin
+	l.height += 2 * margin
+}
@@ -0,0 +1,385 @@
+package diagram
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/diagram"
+	"html"
+	"math"
+	"strings"
+)
+
+// the built-in renderer uses a monospace font, so that text widths can be estimated without font metrics
+const (
+	svgFont        = "monospace"
+	svgFontSize    = 12.0
+	svgCharWidth   = 7.2
+	svgLineHeight  = 16.0
+	svgPadding     = 8.0
+	svgMargin      = 16.0
+	svgLayerGap    = 64.0
+	svgNodeGap     = 16.0
+	svgClassGap    = 56.0
+	svgMarkerSize  = 10.0
+	svgRelationGap = 14.0 // distance between the parallel lines of multiple relations between the same classes
+	svgTextColor   = "#0e0e0d"
+	svgLineColor   = "#41464e"
+	svgFillColor   = "#ffffff"
+)
+
+func textWidth(s string) float64 {
+	return float64(len([]rune(s))) * svgCharWidth
+}
+
+type svgWriter struct {
+	sb   strings.Builder
+	name string // prefixes all ids, so that multiple diagrams can be inlined into the same document
+}
+
+func (w *svgWriter) printf(format string, args ...any) {
+	w.sb.WriteString(fmt.Sprintf(format, args...))
+}
+
+func (w *svgWriter) open(width, height float64) {
+	w.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="%s" font-size="%.0f">`+"\n",
+		width, height, width, height, svgFont, svgFontSize)
+	w.printf("<defs>\n")
+	for _, color := range []string{svgLineColor, highlightColor} {
+		w.printf(`<marker id="%s" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse">`+
+			`<path d="M 0 0 L 10 5 L 0 10 z" fill="%s"/></marker>`+"\n", w.arrow(color), color)
+	}
+	w.printf("</defs>\n")
+}
+
+// arrow returns the marker id of an arrow head in the given color.
+func (w *svgWriter) arrow(color string) string {
+	return w.name + "-arrow-" + strings.TrimPrefix(color, "#")
+}
+
+func (w *svgWriter) close() string {
+	w.sb.WriteString("</svg>\n")
+	return w.sb.String()
+}
+
+func (w *svgWriter) text(x, y float64, anchor, weight, s string) {
+	w.printf(`<text x="%.1f" y="%.1f" text-anchor="%s" font-weight="%s" fill="%s">%s</text>`+"\n",
+		x, y, anchor, weight, svgTextColor, html.EscapeString(s))
+}
+
+func (w *svgWriter) link(id string, draw func()) {
+	if id == "" {
+		draw()
+		return
+	}
+
+	w.printf(`<a href="#%s">`+"\n", html.EscapeString(id))
+	draw()
+	w.printf("</a>\n")
+}
+
+func svgNodeFill(kind diagram.NodeKind) string {
+	switch kind {
+	case diagram.External:
+		return "#eeeeee"
+	case diagram.Stdlib:
+		return "#e8f0fe"
+	default:
+		return svgFillColor
+	}
+}
+
+func svgGraph(g *diagram.Graph) string {
+	l := newLayeredLayout(g, func(n *diagram.Node) (float64, float64) {
+		return textWidth(n.Label) + 2*svgPadding, svgLineHeight + 2*svgPadding
+	})
+	l.place(svgMargin, svgLayerGap, svgNodeGap)
+
+	w := svgWriter{name: g.Name}
+	w.open(l.width, l.height)
+
+	for _, r := range l.routes {
+		color, width := svgLineColor, 1.0
+		if r.edge.Highlight {
+			color, width = highlightColor, 2.0
+		}
+
+		var points []string
+		for i, p := range r.points {
+			switch {
+			case i == 0:
+				points = append(points, fmt.Sprintf("%.1f,%.1f", p.x+p.width, p.y+p.height/2))
+			case i == len(r.points)-1:
+				points = append(points, fmt.Sprintf("%.1f,%.1f", p.x, p.y+p.height/2))
+			default:
+				points = append(points, fmt.Sprintf("%.1f,%.1f", p.x, p.y))
+			}
+		}
+
+		marker := fmt.Sprintf(`marker-end="url(#%s)"`, w.arrow(color))
+		if r.reversed {
+			// the arrow head must point to the original target, which is on the left side now. The marker is
+			// oriented by auto-start-reverse, thus it points backwards at the start of the line.
+			marker = fmt.Sprintf(`marker-start="url(#%s)"`, w.arrow(color))
+		}
+
+		w.printf(`<polyline points="%s" fill="none" stroke="%s" stroke-width="%.0f" %s/>`+"\n",
+			strings.Join(points, " "), color, width, marker)
+	}
+
+	for _, layer := range l.layers {
+		for _, n := range layer {
+			if n.node == nil {
+				continue
+			}
+
+			stroke := svgLineColor
+			if n.node.Highlight {
+				stroke = highlightColor
+			}
+
+			w.link(n.node.Link, func() {
+				w.printf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="6" fill="%s" stroke="%s"/>`+"\n",
+					n.x, n.y, n.width, n.height, svgNodeFill(n.node.Kind), stroke)
+				w.text(n.x+n.width/2, n.y+svgPadding+svgFontSize, "middle", "normal", n.node.Label)
+			})
+		}
+	}
+
+	return w.close()
+}
+
+// classBox is the placement of a class within the grid layout.
+type classBox struct {
+	class         *diagram.Class
+	title         []string
+	x, y          float64
+	width, height float64
+}
+
+func (b *classBox) center() (float64, float64) {
+	return b.x + b.width/2, b.y + b.height/2
+}
+
+// border returns the intersection of the line from the center towards (tx, ty) with the border of the box.
+func (b *classBox) border(tx, ty float64) (float64, float64) {
+	cx, cy := b.center()
+	dx, dy := tx-cx, ty-cy
+	if dx == 0 && dy == 0 {
+		return cx, cy
+	}
+
+	scale := math.Inf(1)
+	if dx != 0 {
+		scale = math.Min(scale, (b.width/2)/math.Abs(dx))
+	}
+	if dy != 0 {
+		scale = math.Min(scale, (b.height/2)/math.Abs(dy))
+	}
+
+	return cx + dx*scale, cy + dy*scale
+}
+
+func newClassBox(c *diagram.Class) *classBox {
+	b := &classBox{class: c}
+	if c.Interface() {
+		b.title = append(b.title, "«interface»")
+	}
+	b.title = append(b.title, classLabel(c, "[", "]"))
+
+	lines := append(append(append([]string{}, b.title...), c.Fields...), c.Methods...)
+	for _, l := range lines {
+		b.width = math.Max(b.width, textWidth(l))
+	}
+	b.width += 2 * svgPadding
+
+	b.height = float64(len(b.title))*svgLineHeight + 2*svgPadding
+	if c.Kind == diagram.Internal {
+		b.height += float64(len(c.Fields)+len(c.Methods))*svgLineHeight + 2*svgPadding
+	}
+
+	return b
+}
+
+// svgClasses arranges the classes in a grid with roughly as many columns as rows and connects them by straight lines.
+func svgClasses(d *diagram.ClassDiagram) string {
+	columns := int(math.Ceil(math.Sqrt(float64(len(d.Classes)))))
+	if columns == 0 {
+		columns = 1
+	}
+	rows := (len(d.Classes) + columns - 1) / columns
+
+	boxes := map[string]*classBox{}
+	colWidths := make([]float64, columns)
+	rowHeights := make([]float64, rows)
+	var ordered []*classBox
+	for i, c := range d.Classes {
+		b := newClassBox(c)
+		boxes[c.ID] = b
+		ordered = append(ordered, b)
+		colWidths[i%columns] = math.Max(colWidths[i%columns], b.width)
+		rowHeights[i/columns] = math.Max(rowHeights[i/columns], b.height)
+	}
+
+	var width, height float64
+	for i, b := range ordered {
+		col, row := i%columns, i/columns
+		b.x = svgMargin
+		for c := 0; c < col; c++ {
+			b.x += colWidths[c] + svgClassGap
+		}
+		b.x += (colWidths[col] - b.width) / 2
+
+		b.y = svgMargin
+		for r := 0; r < row; r++ {
+			b.y += rowHeights[r] + svgClassGap
+		}
+	}
+
+	for _, cw := range colWidths {
+		width += cw + svgClassGap
+	}
+	for _, rh := range rowHeights {
+		height += rh + svgClassGap
+	}
+	width += 2*svgMargin - svgClassGap
+	height += 2*svgMargin - svgClassGap
+
+	w := svgWriter{name: d.Name}
+	w.open(width, height)
+
+	// multiple relations between the same classes are drawn side by side instead of on top of each other
+	type pair struct{ a, b string }
+	pairOf := func(r *diagram.Relation) pair {
+		if r.From < r.To {
+			return pair{r.From, r.To}
+		}
+		return pair{r.To, r.From}
+	}
+
+	parallels := map[pair]int{}
+	for _, r := range d.Relations {
+		parallels[pairOf(r)]++
+	}
+
+	drawn := map[pair]int{}
+	for _, r := range d.Relations {
+		from, to := boxes[r.From], boxes[r.To]
+		if from == nil || to == nil || from == to {
+			continue
+		}
+
+		p := pairOf(r)
+		offset := (float64(drawn[p]) - float64(parallels[p]-1)/2) * svgRelationGap
+		drawn[p]++
+		if p.a != r.From {
+			offset = -offset // the perpendicular flips with the direction of the line
+		}
+
+		svgRelation(&w, r, from, to, offset)
+	}
+
+	for _, b := range ordered {
+		svgClassBox(&w, b)
+	}
+
+	return w.close()
+}
+
+// svgRelation draws the line between the borders of the boxes, which is moved perpendicular by the offset.
+func svgRelation(w *svgWriter, r *diagram.Relation, from, to *classBox, offset float64) {
+	tx, ty := to.center()
+	fx, fy := from.center()
+	x1, y1 := from.border(tx, ty)
+	x2, y2 := to.border(fx, fy)
+	if offset != 0 {
+		_, _, px, py := direction(fx, fy, tx, ty)
+		x1, y1, x2, y2 = x1+px*offset, y1+py*offset, x2+px*offset, y2+py*offset
+	}
+
+	dash := ""
+	if r.Kind == diagram.Realization {
+		dash = ` stroke-dasharray="6,4"`
+	}
+
+	w.printf(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s"%s/>`+"\n", x1, y1, x2, y2, svgLineColor, dash)
+
+	switch r.Kind {
+	case diagram.Composition:
+		w.printf(`<polygon points="%s" fill="%s" stroke="%s"/>`+"\n", diamond(x1, y1, x2, y2), svgLineColor, svgLineColor)
+	case diagram.Aggregation:
+		w.printf(`<polygon points="%s" fill="%s" stroke="%s"/>`+"\n", diamond(x1, y1, x2, y2), svgFillColor, svgLineColor)
+	case diagram.Realization:
+		w.printf(`<polygon points="%s" fill="%s" stroke="%s"/>`+"\n", triangle(x2, y2, x1, y1), svgFillColor, svgLineColor)
+	}
+
+	if r.Label != "" {
+		w.text((x1+x2)/2, (y1+y2)/2-4, "middle", "normal", r.Label)
+	}
+}
+
+// diamond returns the points of a diamond with its tip at (x, y), pointing away from (ox, oy).
+func diamond(x, y, ox, oy float64) string {
+	ux, uy, px, py := direction(x, y, ox, oy)
+	s := svgMarkerSize
+	return fmt.Sprintf("%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f",
+		x, y,
+		x+ux*s+px*s/2, y+uy*s+py*s/2,
+		x+ux*2*s, y+uy*2*s,
+		x+ux*s-px*s/2, y+uy*s-py*s/2)
+}
+
+// triangle returns the points of a triangle with its tip at (x, y), pointing away from (ox, oy).
+func triangle(x, y, ox, oy float64) string {
+	ux, uy, px, py := direction(x, y, ox, oy)
+	s := svgMarkerSize
+	return fmt.Sprintf("%.1f,%.1f %.1f,%.1f %.1f,%.1f",
+		x, y,
+		x+ux*s+px*s/2, y+uy*s+py*s/2,
+		x+ux*s-px*s/2, y+uy*s-py*s/2)
+}
+
+// direction returns the unit vector from (x, y) towards (ox, oy) and its perpendicular.
+func direction(x, y, ox, oy float64) (float64, float64, float64, float64) {
+	dx, dy := ox-x, oy-y
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return 0, 0, 0, 0
+	}
+
+	ux, uy := dx/length, dy/length
+	return ux, uy, -uy, ux
+}
+
+func svgClassBox(w *svgWriter, b *classBox) {
+	c := b.class
+	fill := svgFillColor
+	if c.Kind != diagram.Internal {
+		fill = "#eeeeee"
+	}
+
+	w.link(c.Link, func() {
+		w.printf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" stroke="%s"/>`+"\n",
+			b.x, b.y, b.width, b.height, fill, svgLineColor)
+
+		top := b.y + svgPadding
+		for _, t := range b.title {
+			w.text(b.x+b.width/2, top+svgFontSize, "middle", "bold", t)
+			top += svgLineHeight
+		}
+		top += svgPadding
+
+		if c.Kind != diagram.Internal {
+			return
+		}
+
+		sections := [][]string{c.Fields, c.Methods}
+		for _, lines := range sections {
+			w.printf(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s"/>`+"\n", b.x, top, b.x+b.width, top, svgLineColor)
+			top += svgPadding / 2
+			for _, l := range lines {
+				w.text(b.x+svgPadding, top+svgFontSize, "start", "normal", l)
+				top += svgLineHeight
+			}
+			top += svgPadding / 2
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package diagram
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/diagram"
+	"github.com/worldiety/gdoc/internal/fixture"
+	"regexp"
+	"strings"
+)
+
+func Example_svgGraph() {
+	m, err := fixture.Resolve()
+	if err != nil {
+		panic(err)
+	}
+
+	g := diagram.NewImportGraph(m, diagram.ImportGraphOptions{})
+	rel := func(id string) string { return strings.TrimPrefix(id, fixture.Name+"/") }
+	printLayout := func() {
+		l := newLayeredLayout(g, func(n *diagram.Node) (float64, float64) { return 10, 10 })
+		for i, layer := range l.layers {
+			var labels []string
+			for _, n := range layer {
+				labels = append(labels, n.node.Label)
+			}
+			fmt.Printf("layer %d: %s\n", i, strings.Join(labels, ", "))
+		}
+
+		// the arrow heads of reversed routes are drawn at their start, which is the original target
+		svg := svgGraph(g)
+		markers := regexp.MustCompile(`marker-(start|end)`).FindAllString(svg, -1)
+		for i, r := range l.routes {
+			fmt.Println(rel(r.edge.From), "->", rel(r.edge.To), r.reversed, "starts at", rel(r.points[0].id), markers[i])
+		}
+		fmt.Println(strings.Contains(svg, `orient="auto-start-reverse"`))
+	}
+
+	// store-index imports store/index, thus it is placed in the layer left of it
+	printLayout()
+
+	// an edge back, like in a graph of collapsed packages, is reversed to break the cycle
+	g.AddEdge(fixture.Name+"/store/index", fixture.Name+"/store-index", "")
+	printLayout()
+	// Output:
+	// layer 0: store, store-index
+	// layer 1: store/index
+	// store-index -> store/index false starts at store-index marker-end
+	// true
+	// layer 0: store, store-index
+	// layer 1: store/index
+	// store-index -> store/index false starts at store-index marker-end
+	// store/index -> store-index true starts at store-index marker-start
+	// true
+}
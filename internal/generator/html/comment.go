@@ -0,0 +1,80 @@
+package html
+
+import (
+	"github.com/worldiety/gdoc/internal/highlight"
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+// xref matches the asciidoc cross references, which are inserted into comments while resolving doc links.
+var xref = regexp.MustCompile(`<<([^,<>]+),\s*([^<>]+)>>`)
+
+// Comment converts a doc comment into html. Paragraphs are separated by empty lines, indented lines become
+// highlighted preformatted blocks and resolved doc links become anchors.
+func Comment(s string) template.HTML {
+	var sb strings.Builder
+	var pre, par []string
+	flush := func() {
+		if len(par) > 0 {
+			sb.WriteString("<p>" + links(strings.Join(par, "\n")) + "</p>\n")
+			par = nil
+		}
+		if len(pre) > 0 {
+			sb.WriteString("<pre>" + code(strings.Join(pre, "\n")) + "</pre>\n")
+			pre = nil
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+		case strings.HasPrefix(line, "\t") || strings.HasPrefix(line, " "):
+			if len(par) > 0 {
+				flush()
+			}
+			pre = append(pre, strings.TrimPrefix(line, "\t"))
+		case strings.HasPrefix(line, "# "):
+			flush()
+			sb.WriteString("<h5>" + links(strings.TrimPrefix(line, "# ")) + "</h5>\n")
+		default:
+			if len(pre) > 0 {
+				flush()
+			}
+			par = append(par, line)
+		}
+	}
+	flush()
+
+	return template.HTML(sb.String())
+}
+
+// Text returns the plain text of a doc comment, without the markup of resolved doc links.
+func Text(s string) string {
+	return strings.TrimSpace(xref.ReplaceAllString(s, "$2"))
+}
+
+// links escapes the text and turns all cross references into anchors.
+func links(s string) string {
+	return anchors(s, html.EscapeString)
+}
+
+// code highlights a preformatted block and turns all cross references into anchors.
+func code(s string) string {
+	return anchors(s, highlight.HTML)
+}
+
+// anchors converts the text between the cross references with the given function.
+func anchors(s string, text func(string) string) string {
+	var sb strings.Builder
+	last := 0
+	for _, m := range xref.FindAllStringSubmatchIndex(s, -1) {
+		sb.WriteString(text(s[last:m[0]]))
+		sb.WriteString(`<a href="#` + html.EscapeString(s[m[2]:m[3]]) + `">` + html.EscapeString(s[m[4]:m[5]]) + `</a>`)
+		last = m[1]
+	}
+	sb.WriteString(text(s[last:]))
+	return sb.String()
+}
@@ -0,0 +1,337 @@
+// Package html renders an api.Module as a self-contained html page, which requires no external tools.
+package html
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/highlight"
+	"html"
+	"html/template"
+	"sort"
+	"strings"
+)
+
+//go:embed templates
+var templateFiles embed.FS
+
+// templates binds all template files in the html/templates directory.
+var templates = template.Must(template.ParseFS(templateFiles, "templates/*.gohtml"))
+
+const pageTemplate = "page"
+
+// Options contain everything, which is not part of the module itself.
+type Options struct {
+	Version  string    // the documented version, if any
+	Versions []Version // the entries of the version selector, which is hidden if empty
+	// Changes links changed symbols, keyed by their anchor id, to the same symbol in neighbouring versions.
+	Changes map[string][]VersionLink
+	// Diagrams are rendered svg images, keyed by the import path of their package. The module diagrams use the
+	// empty key.
+	Diagrams map[api.ImportPath][]template.HTML
+	// Head is inserted into the html head, e.g. to add scripts.
+	Head template.HTML
+}
+
+// Version is an entry of the version selector.
+type Version struct {
+	Name    string
+	Href    string // relative to the current page
+	Current bool
+}
+
+// VersionLink refers to a symbol in another version, which declares it differently.
+type VersionLink struct {
+	Version string
+	Href    string
+}
+
+// Page is the data model of the page template.
+type Page struct {
+	Options
+	Name         string
+	Readme       template.HTML
+	Packages     []Package
+	Deprecations []Deprecation
+}
+
+// Deprecation is a row of the deprecated APIs table.
+type Deprecation struct {
+	ID      string
+	Name    string
+	Kind    string
+	Package string
+	api.Deprecation
+}
+
+type Package struct {
+	ID         string // the anchor, which equals the package name like in the asciidoc output
+	Name       string
+	ImportPath string
+	Deprecated *api.Deprecation
+	Source     template.URL // the url of the package clause, see api.Position
+	Doc        template.HTML
+	Readme     template.HTML
+	Diagrams   []template.HTML
+	Consts     []Symbol
+	Vars       []Symbol
+	Types      []Type
+	Funcs      []Symbol
+}
+
+// Symbol is any documented declaration.
+type Symbol struct {
+	ID         string // the anchor, see api.RefId ID
+	Name       string
+	Decl       template.HTML // the declaration in Go syntax with linked types
+	Doc        template.HTML
+	Since      string
+	Deprecated *api.Deprecation
+	Source     template.URL  // the url of the declaration, see api.Position
+	Body       template.HTML // the numbered source lines of functions and methods, if requested
+	Changes    []VersionLink
+}
+
+type Type struct {
+	Symbol
+	Kind         string // struct, interface or empty
+	Constructors []Symbol
+	Methods      []Symbol
+	ReferencedBy []Reference
+}
+
+type Reference struct {
+	ID   string
+	Name string
+	Kind string
+}
+
+// Render creates a single html page documenting the module.
+func Render(m *api.Module, opts Options) ([]byte, error) {
+	page := NewPage(m, opts)
+
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, pageTemplate, page); err != nil {
+		return nil, fmt.Errorf("unable to execute %s: %w", pageTemplate, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// NewPage creates the data model of the page template.
+func NewPage(m *api.Module, opts Options) Page {
+	page := Page{
+		Options: opts,
+		Name:    m.Name,
+		Readme:  Comment(m.Readme),
+	}
+
+	paths := make([]string, 0, len(m.Packages))
+	for path := range m.Packages {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		p := m.Packages[path]
+		page.Packages = append(page.Packages, newPackage(m, p, opts))
+		if p.Deprecated != nil {
+			page.Deprecations = append(page.Deprecations, Deprecation{ID: m.Anchor(p.PackageDefinition.Package()), Name: p.Name, Kind: "package", Package: path, Deprecation: *p.Deprecated})
+		}
+	}
+
+	for _, sym := range m.Symbols() {
+		if sym.Deprecated != nil {
+			page.Deprecations = append(page.Deprecations, Deprecation{
+				ID:          m.Anchor(sym.Ref),
+				Name:        sym.Name,
+				Kind:        string(sym.Kind),
+				Package:     sym.Package,
+				Deprecation: *sym.Deprecated,
+			})
+		}
+	}
+
+	return page
+}
+
+func newPackage(m *api.Module, p *api.Package, opts Options) Package {
+	res := Package{
+		ID:         m.Anchor(p.PackageDefinition.Package()),
+		Name:       p.Name,
+		ImportPath: p.PackageDefinition.ImportPath,
+		Deprecated: p.Deprecated,
+		Source:     template.URL(p.Position.URL),
+		Doc:        Comment(p.Doc),
+		Readme:     Comment(p.Readme),
+		Diagrams:   opts.Diagrams[p.PackageDefinition.ImportPath],
+	}
+
+	symbol := func(ref api.RefId, decl template.HTML, doc, since string, deprecated *api.Deprecation, pos api.Position) Symbol {
+		return Symbol{
+			ID:         m.Anchor(ref),
+			Name:       ref.Identifier,
+			Decl:       decl,
+			Doc:        Comment(doc),
+			Since:      since,
+			Deprecated: deprecated,
+			Source:     template.URL(pos.URL),
+			Changes:    opts.Changes[m.Anchor(ref)],
+		}
+	}
+
+	for _, block := range p.Consts {
+		for _, c := range block.Content {
+			decl := "const " + c.RefId.Identifier
+			if c.Expr != "" {
+				decl += " = " + c.Expr
+			}
+			res.Consts = append(res.Consts, symbol(c.RefId, template.HTML(html.EscapeString(decl)), c.Comment+"\n\n"+block.Doc, c.Since, c.Deprecated, c.Position))
+		}
+	}
+
+	for _, name := range sortedKeys(p.Vars) {
+		v := p.Vars[name]
+		decl := "var " + name + " " + typeLink(m, v.TypeDesc)
+		res.Vars = append(res.Vars, symbol(v.Definition, template.HTML(decl), v.Doc+"\n\n"+v.Comment, v.Since, v.Deprecated, v.Position))
+	}
+
+	for _, name := range sortedKeys(p.Functions) {
+		fn := p.Functions[name]
+		sym := symbol(fn.TypeDefinition, funcDecl(fn, nil), fn.Comment, fn.Since, fn.Deprecated, fn.Position)
+		sym.Body = body(fn)
+		res.Funcs = append(res.Funcs, sym)
+	}
+
+	for _, name := range sortedKeys(p.Structs) {
+		s := p.Structs[name]
+		t := Type{Symbol: symbol(s.TypeDefinition, typeDecl(m, s), s.Comment, s.Since, s.Deprecated, s.Position)}
+		switch {
+		case s.Interface():
+			t.Kind = api.StereotypeInterface
+		case len(s.Stereotypes) > 0:
+			t.Kind = api.StereotypeStruct
+		}
+
+		fns := append([]*api.Function{}, s.Constructors...)
+		sort.Slice(fns, func(i, j int) bool { return fns[i].Name < fns[j].Name })
+		for _, fn := range fns {
+			sym := symbol(fn.TypeDefinition, funcDecl(fn, nil), fn.Comment, fn.Since, fn.Deprecated, fn.Position)
+			sym.Body = body(fn)
+			t.Constructors = append(t.Constructors, sym)
+		}
+
+		methods := append([]*api.Method{}, s.Methods...)
+		sort.Slice(methods, func(i, j int) bool { return methods[i].Name < methods[j].Name })
+		for _, method := range methods {
+			sym := symbol(method.TypeDefinition, funcDecl(method.Function, method.Recv), method.Comment, method.Since, method.Deprecated, method.Position)
+			sym.Body = body(method.Function)
+			t.Methods = append(t.Methods, sym)
+		}
+
+		for _, r := range s.ReferencedBy {
+			name := r.From.Identifier
+			if r.From.ImportPath != s.TypeDefinition.ImportPath {
+				name = r.From.PackageName() + "." + name
+			}
+			t.ReferencedBy = append(t.ReferencedBy, Reference{ID: m.Anchor(r.From), Name: name, Kind: strings.TrimSpace(string(r.Kind) + " " + r.Name)})
+		}
+
+		res.Types = append(res.Types, t)
+	}
+
+	return res
+}
+
+// body renders the highlighted source lines of a function, numbered like in its file.
+func body(fn *api.Function) template.HTML {
+	if fn.Body == "" {
+		return ""
+	}
+
+	var sb strings.Builder
+	for i, tokens := range highlight.Lines(highlight.Tokens(fn.Body)) {
+		sb.WriteString(fmt.Sprintf(`<span class="line" data-line="%d">%s</span>`+"\n", fn.Position.Line+i, highlight.HTMLTokens(tokens)))
+	}
+	return template.HTML(sb.String())
+}
+
+// typeDecl renders the type declaration including the fields of structs and the methods of interfaces.
+func typeDecl(m *api.Module, s *api.Struct) template.HTML {
+	var sb strings.Builder
+	sb.WriteString("type " + html.EscapeString(s.Name))
+	if len(s.Generics) > 0 {
+		var params []string
+		for _, g := range s.Generics {
+			params = append(params, html.EscapeString(g.Name)+" "+typeLink(m, g.TypeDesc))
+		}
+		sb.WriteString("[" + strings.Join(params, ", ") + "]")
+	}
+
+	switch {
+	case s.Interface():
+		sb.WriteString(" interface {\n")
+		for _, method := range s.Methods {
+			sb.WriteString("\t" + html.EscapeString(method.Signature) + "\n")
+		}
+		sb.WriteString("}")
+	case len(s.Stereotypes) > 0:
+		sb.WriteString(" struct {\n")
+		for _, f := range s.Fields {
+			sb.WriteString("\t")
+			if f.Definition.Named() {
+				name := html.EscapeString(f.Name)
+				if f.Deprecated != nil {
+					name = `<s class="deprecated">` + name + `</s>`
+				}
+				sb.WriteString(`<span id="` + m.Anchor(f.Definition) + `">` + name + `</span> `)
+			}
+			sb.WriteString(typeLink(m, f.TypeDesc))
+			if c := strings.TrimSpace(f.Comment); c != "" {
+				sb.WriteString(` <span class="comment">// ` + links(strings.ReplaceAll(c, "\n", " ")) + `</span>`)
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("}")
+	}
+
+	return template.HTML(sb.String())
+}
+
+func funcDecl(fn *api.Function, recv *api.Recv) template.HTML {
+	decl := "func "
+	if recv != nil {
+		decl += "(" + strings.TrimSpace(recv.Name+" "+recv.TypeString) + ") "
+	}
+	return template.HTML(html.EscapeString(decl + fn.Signature))
+}
+
+// typeLink renders the source of the type and links its identifier, if it is declared in the module.
+func typeLink(m *api.Module, td *api.TypeDesc) string {
+	if td == nil {
+		return ""
+	}
+
+	src := html.EscapeString(td.SrcTypeDefinition)
+	ref := td.TypeDefinition
+	if p := m.Packages[ref.ImportPath]; p == nil || p.Structs[ref.Identifier] == nil {
+		return src
+	}
+
+	idx := strings.LastIndex(src, ref.Identifier)
+	if idx < 0 {
+		return src
+	}
+
+	return src[:idx] + `<a href="#` + m.Anchor(ref) + `">` + ref.Identifier + `</a>` + src[idx+len(ref.Identifier):]
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
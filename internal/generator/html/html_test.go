@@ -0,0 +1,49 @@
+package html
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/fixture"
+	"strings"
+)
+
+func ExampleRender() {
+	m, err := fixture.Resolve()
+	if err != nil {
+		panic(err)
+	}
+	m.Anchors = api.NewAnchors(api.ReadableAnchors{Module: m.Name}, m)
+
+	page, err := Render(m, Options{
+		Version: "v2",
+		Versions: []Version{
+			{Name: "v1", Href: "../v1/index.html"},
+			{Name: "v2", Href: "../v2/index.html", Current: true},
+		},
+		Changes: map[string][]VersionLink{"store--NewStore": {{Version: "v1", Href: "../v1/index.html#store--NewStore"}}},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// the version selector and the link of the changed symbol to the previous version
+	var article string
+	for _, line := range strings.Split(string(page), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "<article"):
+			article = line
+		case strings.Contains(line, `class="changed"`):
+			fmt.Println(article)
+			fmt.Println(line)
+		case strings.Contains(line, "<title>") || strings.Contains(line, "<option"):
+			fmt.Println(line)
+		}
+	}
+	// Output:
+	// <title>example.com/m v2</title>
+	// <option value="../v1/index.html">v1</option>
+	// <option value="../v2/index.html" selected>v2</option>
+	// <article class="symbol" id="store--NewStore">
+	// <a class="changed" href="../v1/index.html#store--NewStore">v1</a>
+}
@@ -0,0 +1,140 @@
+// Package search builds the index of the client-side search, which is shipped next to the html output together
+// with a dependency-free script. The entries use the same anchors as the generators, so results jump to the
+// documented declaration.
+package search
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/generator/html"
+	"sort"
+	"strings"
+)
+
+const (
+	IndexFile  = "search.json"
+	ScriptFile = "search.js"
+	// DocinfoFile is included by asciidoctor at the end of the html body, because the generated adoc declares a
+	// shared docinfo. The docinfo.html of the head is left to the user.
+	DocinfoFile = "docinfo-footer.html"
+
+	// KindPackage marks the entries of packages, which have no api.SymbolKind.
+	KindPackage api.SymbolKind = "package"
+
+	// maxDoc is the number of characters of the doc text, which is indexed.
+	maxDoc = 240
+)
+
+// Script loads the index relative to its own location and adds a search box to the page, unless the page already
+// contains an input with the id gdoc-search. It also redirects links to legacy hash anchors using anchors.json.
+//
+//go:embed search.js
+var Script []byte
+
+// Docinfo loads the script into the html page, which asciidoctor renders.
+var Docinfo = []byte(`<script src="` + ScriptFile + `" defer></script>` + "\n")
+
+// Entry is a searchable declaration. The short json names keep the index compact.
+type Entry struct {
+	ID          string           `json:"i"` // the anchor, see api.RefId ID
+	Name        string           `json:"n"`
+	Qualified   string           `json:"q"` // the name qualified by the package name
+	Kind        api.SymbolKind   `json:"k"`
+	Stereotypes []api.Stereotype `json:"s,omitempty"`
+	Doc         string           `json:"d,omitempty"` // the beginning of the doc comment as plain text
+}
+
+// Index returns an entry for each package and each exported declaration of the module, sorted like api.Module
+// Symbols.
+func Index(m *api.Module) []Entry {
+	docs := map[string]string{}
+	stereotypes := map[string][]api.Stereotype{}
+	add := func(ref api.RefId, doc string, st ...api.Stereotype) {
+		docs[m.Anchor(ref)] = doc
+		stereotypes[m.Anchor(ref)] = append(stereotypes[m.Anchor(ref)], st...)
+	}
+
+	var res []Entry
+	for _, path := range sortedKeys(m.Packages) {
+		p := m.Packages[path]
+		res = append(res, Entry{ID: m.Anchor(p.PackageDefinition.Package()), Name: p.Name, Qualified: path, Kind: KindPackage, Stereotypes: p.Stereotypes, Doc: text(p.Doc)})
+
+		for _, block := range p.Consts {
+			for _, c := range block.Content {
+				add(c.RefId, c.Comment+"\n"+block.Doc)
+			}
+		}
+		for _, v := range p.Vars {
+			add(v.Definition, v.Doc+"\n"+v.Comment, v.Stereotypes...)
+		}
+		for _, fn := range p.Functions {
+			add(fn.TypeDefinition, fn.Comment)
+		}
+		for _, s := range p.Structs {
+			add(s.TypeDefinition, s.Comment, s.Stereotypes...)
+			for _, fn := range s.Constructors {
+				add(fn.TypeDefinition, fn.Comment, api.StereotypeConstructor)
+			}
+			for _, method := range s.Methods {
+				add(method.TypeDefinition, method.Comment, api.StereotypeMethod)
+			}
+			for _, f := range s.Fields {
+				add(f.Definition, f.Doc+"\n"+f.Comment, f.Stereotypes...)
+			}
+		}
+	}
+
+	for _, sym := range m.Symbols() {
+		st := stereotypes[m.Anchor(sym.Ref)]
+		if sym.Deprecated != nil {
+			st = append(st, api.StereotypeDeprecated)
+		}
+
+		res = append(res, Entry{
+			ID:          m.Anchor(sym.Ref),
+			Name:        sym.Name,
+			Qualified:   sym.Ref.PackageName() + "." + sym.Name,
+			Kind:        sym.Kind,
+			Stereotypes: st,
+			Doc:         text(docs[m.Anchor(sym.Ref)]),
+		})
+	}
+
+	return res
+}
+
+// Files returns the index and the script, which belong next to the html page.
+func Files(m *api.Module) (map[string][]byte, error) {
+	buf, err := json.Marshal(Index(m))
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal search index: %w", err)
+	}
+
+	return map[string][]byte{IndexFile: buf, ScriptFile: Script}, nil
+}
+
+// text returns the beginning of a doc comment as single line of plain text.
+func text(doc string) string {
+	s := strings.Join(strings.Fields(html.Text(doc)), " ")
+	runes := []rune(s)
+	if len(runes) <= maxDoc {
+		return s
+	}
+
+	s = string(runes[:maxDoc])
+	if i := strings.LastIndex(s, " "); i > 0 {
+		s = s[:i]
+	}
+	return s + " …"
+}
+
+func sortedKeys[V any](m map[api.ImportPath]V) []api.ImportPath {
+	keys := make([]api.ImportPath, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,162 @@
+// search.js loads search.json from its own directory and jumps to the anchor of the selected declaration.
+// Results are ranked by exact, prefix, substring and fuzzy matches of the names and finally by the doc text.
+// Links to hash anchors, which the page does not contain, are redirected using anchors.json, if present.
+(function () {
+    "use strict";
+
+    const limit = 20;
+    const base = document.currentScript ? document.currentScript.src : location.href;
+    const entries = fetch(new URL("search.json", base)).then(r => r.json());
+
+    function rank(e, q) {
+        const names = [e.n.toLowerCase(), e.q.toLowerCase()];
+        if (names.includes(q)) return 0;
+        if (names.some(n => n.startsWith(q) || n.includes("." + q))) return 1;
+        if (names.some(n => n.includes(q))) return 2;
+        if (names.some(n => fuzzy(n, q))) return 3;
+        if (e.d && e.d.toLowerCase().includes(q)) return 4;
+        return -1;
+    }
+
+    // fuzzy reports whether all characters of q appear in s in the same order.
+    function fuzzy(s, q) {
+        let i = 0;
+        for (const c of s) {
+            if (c === q[i]) i++;
+            if (i === q.length) return true;
+        }
+        return false;
+    }
+
+    function find(list, query) {
+        const q = query.trim().toLowerCase();
+        if (!q) return [];
+        return list
+            .map((e, i) => ({e, i, r: rank(e, q)}))
+            .filter(m => m.r >= 0)
+            .sort((a, b) => a.r - b.r || a.e.n.length - b.e.n.length || a.i - b.i)
+            .slice(0, limit)
+            .map(m => m.e);
+    }
+
+    function input() {
+        let el = document.getElementById("gdoc-search");
+        if (el) return el;
+
+        el = document.createElement("input");
+        el.id = "gdoc-search";
+        el.type = "search";
+        el.placeholder = "Search";
+        el.style.cssText = "position:fixed;top:.5em;right:.5em;z-index:100;width:16em;padding:.3em;";
+        document.body.appendChild(el);
+        return el;
+    }
+
+    function init() {
+        const box = input();
+        box.autocomplete = "off";
+        const results = document.createElement("ul");
+        results.className = "search-results";
+        results.style.cssText = "position:fixed;z-index:100;margin:0;padding:0;list-style:none;max-height:70vh;overflow-y:auto;" +
+            "background:#fff;color:#222;border:1px solid #ccc;font-size:.9em;display:none;";
+        box.insertAdjacentElement("afterend", results);
+
+        let matches = [];
+        let selected = 0;
+
+        function render() {
+            results.replaceChildren(...matches.map((e, i) => {
+                const li = document.createElement("li");
+                li.style.cssText = "padding:.3em .5em;cursor:pointer;" + (i === selected ? "background:#e8eef7;" : "");
+                const name = document.createElement("strong");
+                name.textContent = e.q;
+                const kind = document.createElement("small");
+                kind.textContent = " " + [e.k].concat(e.s || []).join(", ");
+                li.append(name, kind);
+                if (e.d) {
+                    const doc = document.createElement("div");
+                    doc.textContent = e.d;
+                    doc.style.cssText = "color:#666;white-space:nowrap;overflow:hidden;text-overflow:ellipsis;max-width:40em;";
+                    li.append(doc);
+                }
+                li.addEventListener("mousedown", ev => {
+                    ev.preventDefault();
+                    jump(e);
+                });
+                return li;
+            }));
+
+            // fixed, so that scrolling or clipping containers like a sidebar do not hide the results
+            const rect = box.getBoundingClientRect();
+            results.style.top = rect.bottom + "px";
+            results.style.left = Math.max(0, Math.min(rect.left, window.innerWidth - 300)) + "px";
+            results.style.minWidth = rect.width + "px";
+            results.style.display = matches.length ? "block" : "none";
+        }
+
+        function jump(e) {
+            location.hash = e.i;
+            matches = [];
+            render();
+        }
+
+        box.addEventListener("input", () => entries.then(list => {
+            matches = find(list, box.value);
+            selected = 0;
+            render();
+        }));
+
+        box.addEventListener("keydown", ev => {
+            switch (ev.key) {
+                case "ArrowDown":
+                    selected = Math.min(selected + 1, matches.length - 1);
+                    break;
+                case "ArrowUp":
+                    selected = Math.max(selected - 1, 0);
+                    break;
+                case "Enter":
+                    if (matches[selected]) jump(matches[selected]);
+                    break;
+                case "Escape":
+                    box.value = "";
+                    matches = [];
+                    break;
+                default:
+                    return;
+            }
+            ev.preventDefault();
+            render();
+        });
+
+        box.addEventListener("blur", () => {
+            matches = [];
+            render();
+        });
+    }
+
+    // redirect follows a link to a legacy hash anchor like #gd4f1c…, which has been replaced by another scheme.
+    function redirect() {
+        const id = decodeURIComponent(location.hash.slice(1));
+        if (!/^gd[0-9a-f]{56}$/.test(id) || document.getElementById(id)) return;
+        fetch(new URL("anchors.json", base))
+            .then(r => r.ok ? r.json() : {})
+            .then(legacy => {
+                if (legacy[id]) location.replace("#" + legacy[id]);
+            })
+            .catch(() => {
+            });
+    }
+
+    window.addEventListener("hashchange", redirect);
+
+    function start() {
+        init();
+        redirect();
+    }
+
+    if (document.readyState === "loading") {
+        document.addEventListener("DOMContentLoaded", start);
+    } else {
+        start();
+    }
+})();
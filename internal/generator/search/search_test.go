@@ -0,0 +1,48 @@
+package search
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/fixture"
+	"strings"
+	"unicode/utf8"
+)
+
+func ExampleIndex() {
+	m, err := fixture.Resolve()
+	if err != nil {
+		panic(err)
+	}
+
+	for _, e := range Index(m) {
+		fmt.Printf("%s %s %v %q\n", e.Kind, e.Qualified, e.Stereotypes, e.Doc)
+	}
+	// Output:
+	// package example.com/m/store [] "Package store keeps the entities of the example module."
+	// package example.com/m/store-index [deprecated] "Package storeindex is the former home of the index package. Deprecated: use example.com/m/store/index instead."
+	// package example.com/m/store/index [] "Package index looks up the entities of a store by other fields than their id."
+	// const store.Append [] "The modes of a Store."
+	// var store.DefaultMode [] "DefaultMode is used, if no mode is given."
+	// func store.ExampleNewStore [] ""
+	// func store-index.Index [deprecated] "Index returns the name of the indexed field. Deprecated: use the Field of the Index in store/index instead."
+	// type index.Index [struct] "Index maps the values of a field to the ids of the entities."
+	// field index.Index.Field [property] "the name of the indexed field"
+	// type store.Mode [] "Mode controls, how a Store writes entities. The Mode of a [Tenant] is looked up by modes[Tenant]"
+	// func store.NewStore [constructor] "NewStore creates an empty Store."
+	// const store.ReadOnly [] "The modes of a Store."
+	// const store.ReadWrite [] "allows all writes The modes of a Store."
+	// type store.Store [struct] "Store keeps the entities in memory."
+	// field store.Store.Mode [property] "controls the writes"
+	// var store.Strict [] ""
+}
+
+func Example_text() {
+	fmt.Println(text("Store keeps\nthe   entities.\n\n\tcode"))
+	long := text(strings.Repeat("entity ", 50))
+	fmt.Println(strings.HasSuffix(long, "entity …"), len(long) <= maxDoc+len(" …"))
+	umlauts := text(strings.Repeat("ä", maxDoc+1))
+	fmt.Println(utf8.ValidString(umlauts), utf8.RuneCountInString(umlauts) <= maxDoc+utf8.RuneCountInString(" …"))
+	// Output:
+	// Store keeps the entities. code
+	// true true
+	// true true
+}
@@ -0,0 +1,667 @@
+// Package terminal renders the documentation of a package or of a single symbol for the shell, similar to go doc.
+package terminal
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/highlight"
+	"go/format"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// xref matches the asciidoc cross references, which are inserted into comments while resolving doc links.
+var xref = regexp.MustCompile(`<<([^,<>]+),\s*([^<>]+)>>`)
+
+const indent = "    "
+
+// Options configures the rendering.
+type Options struct {
+	Plain bool // omit colors and hyperlinks, so that pagers and files show plain text
+}
+
+// Show renders the package or symbol, which matches the query. A query is a package, optionally followed by a
+// symbol path like pkg.Func, pkg.Type, pkg.Type.Method or pkg.Type.Field.Field. Packages are matched by import
+// path, by the path relative to the module or by name.
+func Show(m *api.Module, query string, opts Options) (string, error) {
+	p, path, err := lookup(m, query)
+	if err != nil {
+		return "", err
+	}
+
+	w := &printer{m: m, p: p, opts: opts, urls: urls(m)}
+	if len(path) == 0 {
+		w.pkg()
+		return w.sb.String(), nil
+	}
+
+	if err := w.symbol(path); err != nil {
+		return "", err
+	}
+
+	return w.sb.String(), nil
+}
+
+// lookup returns the package with the longest name matching the query and the remaining symbol path.
+func lookup(m *api.Module, query string) (*api.Package, []string, error) {
+	var best *api.Package
+	var rest string
+	longest := 0
+	for _, path := range sortedKeys(m.Packages) {
+		p := m.Packages[path]
+		for _, name := range []string{path, strings.TrimPrefix(path, m.Name+"/"), p.Name} {
+			if len(name) <= longest {
+				continue
+			}
+
+			switch {
+			case query == name:
+				best, rest, longest = p, "", len(name)
+			case strings.HasPrefix(query, name+"."):
+				best, rest, longest = p, query[len(name)+1:], len(name)
+			}
+		}
+	}
+
+	if best == nil {
+		return nil, nil, fmt.Errorf("no package matches %s", query)
+	}
+
+	if rest == "" {
+		return best, nil, nil
+	}
+
+	return best, strings.Split(rest, "."), nil
+}
+
+// urls maps the anchors of all declarations to their source, see api.Position.
+func urls(m *api.Module) map[string]string {
+	res := map[string]string{}
+	add := func(id string, p api.Position) {
+		if p.URL != "" {
+			res[id] = p.URL
+		}
+	}
+
+	for _, p := range m.Packages {
+		add(p.Name, p.Position)
+		for _, block := range p.Consts {
+			for _, c := range block.Content {
+				add(m.Anchor(c.RefId), c.Position)
+			}
+		}
+		for _, v := range p.Vars {
+			add(m.Anchor(v.Definition), v.Position)
+		}
+		for _, fn := range p.Functions {
+			add(m.Anchor(fn.TypeDefinition), fn.Position)
+		}
+		for _, s := range p.Structs {
+			add(m.Anchor(s.TypeDefinition), s.Position)
+			for _, fn := range s.Constructors {
+				add(m.Anchor(fn.TypeDefinition), fn.Position)
+			}
+			for _, method := range s.Methods {
+				add(m.Anchor(method.TypeDefinition), method.Position)
+			}
+			for _, f := range s.Fields {
+				add(m.Anchor(f.Definition), f.Position)
+			}
+		}
+	}
+
+	return res
+}
+
+type printer struct {
+	sb   strings.Builder
+	m    *api.Module
+	p    *api.Package
+	opts Options
+	urls map[string]string // the source of each anchor
+}
+
+// pkg prints the package doc followed by a summary of all declarations.
+func (w *printer) pkg() {
+	w.line(w.code("package "+w.p.Name) + w.style("90", ` // import "`+w.p.PackageDefinition.ImportPath+`"`))
+	w.line("")
+	w.deprecated(w.p.Deprecated)
+	w.doc(w.p.Doc, "")
+
+	var consts []string
+	for _, block := range w.p.Consts {
+		for _, c := range block.Content {
+			consts = append(consts, constDecl(c))
+		}
+	}
+	w.section("CONSTANTS", consts)
+
+	var vars []string
+	for _, name := range sortedKeys(w.p.Vars) {
+		vars = append(vars, varDecl(w.p.Vars[name]))
+	}
+	w.section("VARIABLES", vars)
+
+	var funcs []string
+	for _, name := range sortedKeys(w.p.Functions) {
+		if fn := w.p.Functions[name]; !test(fn) {
+			funcs = append(funcs, funcDecl(fn, nil))
+		}
+	}
+	w.section("FUNCTIONS", funcs)
+
+	var types []string
+	for _, name := range sortedKeys(w.p.Structs) {
+		s := w.p.Structs[name]
+		types = append(types, "type "+s.Name+" "+kind(s))
+		for _, fn := range s.Constructors {
+			types = append(types, indent+funcDecl(fn, nil))
+		}
+	}
+	w.section("TYPES", types)
+
+	w.examples("")
+}
+
+// symbol prints a single declaration of the package.
+func (w *printer) symbol(path []string) error {
+	name := path[0]
+	if len(path) == 1 {
+		for _, block := range w.p.Consts {
+			for _, c := range block.Content {
+				if c.RefId.Identifier == name {
+					w.line(w.code(constDecl(c)))
+					w.deprecated(c.Deprecated)
+					w.doc(c.Comment+"\n\n"+block.Doc, indent)
+					w.position(c.Position)
+					return nil
+				}
+			}
+		}
+
+		if v, ok := w.p.Vars[name]; ok {
+			w.line(w.code(varDecl(v)))
+			w.deprecated(v.Deprecated)
+			w.doc(v.Doc+"\n\n"+v.Comment, indent)
+			w.position(v.Position)
+			return nil
+		}
+
+		if fn, ok := w.p.Functions[name]; ok {
+			w.function(fn, nil)
+			w.examples(name)
+			return nil
+		}
+
+		// go doc shows constructors as package level functions, like pkg.NewType
+		for _, typeName := range sortedKeys(w.p.Structs) {
+			for _, fn := range w.p.Structs[typeName].Constructors {
+				if fn.Name == name {
+					w.function(fn, nil)
+					w.examples(name)
+					return nil
+				}
+			}
+		}
+	}
+
+	s, ok := w.p.Structs[name]
+	if !ok {
+		return fmt.Errorf("no symbol %s in package %s", strings.Join(path, "."), w.p.PackageDefinition.ImportPath)
+	}
+
+	if len(path) == 1 {
+		w.typ(s)
+		return nil
+	}
+
+	member := path[1]
+	if len(path) == 2 {
+		for _, fn := range s.Constructors {
+			if fn.Name == member {
+				w.function(fn, nil)
+				w.examples(member)
+				return nil
+			}
+		}
+
+		for _, method := range s.Methods {
+			if method.Name == member {
+				w.function(method.Function, method.Recv)
+				w.examples(s.Name + "_" + member)
+				return nil
+			}
+		}
+	}
+
+	// follow the field path through the structs of the module
+	for i, name := range path[1:] {
+		f := field(s, name)
+		if f == nil {
+			return fmt.Errorf("no field or method %s in %s", strings.Join(path[:i+2], "."), s.TypeDefinition.ImportPath)
+		}
+
+		if i == len(path)-2 {
+			w.field(s, f)
+			return nil
+		}
+
+		next := w.lookupStruct(f.TypeDesc)
+		if next == nil {
+			return fmt.Errorf("the type of %s is not declared in the module", strings.Join(path[:i+2], "."))
+		}
+		s = next
+	}
+
+	return nil
+}
+
+// typ prints a type with its members, examples, implementations and references.
+func (w *printer) typ(s *api.Struct) {
+	w.line(w.code(gofmt(typeDecl(s))))
+	w.deprecated(s.Deprecated)
+	w.doc(s.Comment, indent)
+
+	var constructors []string
+	for _, fn := range s.Constructors {
+		constructors = append(constructors, funcDecl(fn, nil))
+	}
+	w.section("CONSTRUCTORS", constructors)
+
+	if !s.Interface() {
+		var methods []string
+		for _, method := range sortedMethods(s.Methods) {
+			methods = append(methods, funcDecl(method.Function, method.Recv))
+		}
+		w.section("METHODS", methods)
+	}
+
+	var implements []string
+	for _, ref := range s.Implements {
+		implements = append(implements, w.ref(ref))
+	}
+	w.list("IMPLEMENTS", implements)
+
+	if s.Interface() {
+		var implementers []string
+		for _, path := range sortedKeys(w.m.Packages) {
+			p := w.m.Packages[path]
+			for _, name := range sortedKeys(p.Structs) {
+				for _, ref := range p.Structs[name].Implements {
+					if ref == s.TypeDefinition {
+						implementers = append(implementers, w.ref(p.Structs[name].TypeDefinition))
+					}
+				}
+			}
+		}
+		w.list("IMPLEMENTED BY", implementers)
+	}
+
+	w.examples(s.Name)
+	w.references(s)
+	w.position(s.Position)
+}
+
+// function prints the signature and doc of a function or method.
+func (w *printer) function(fn *api.Function, recv *api.Recv) {
+	w.line(w.code(funcDecl(fn, recv)))
+	w.deprecated(fn.Deprecated)
+	w.doc(fn.Comment, indent)
+	w.position(fn.Position)
+}
+
+// field prints a single field within its struct, like go doc does.
+func (w *printer) field(s *api.Struct, f *api.Field) {
+	decl := "type " + s.Name + " struct {\n\t" + strings.TrimSpace(f.Name+" "+typeString(f.TypeDesc))
+	if c := strings.TrimSpace(f.Comment); c != "" {
+		decl += " // " + strings.ReplaceAll(text(c), "\n", " ")
+	}
+	if len(s.Fields) > 1 {
+		decl += "\n\n\t// other fields elided ..."
+	}
+	w.line(w.code(gofmt(decl + "\n}")))
+	w.deprecated(f.Deprecated)
+	w.doc(f.Doc, indent)
+	w.position(f.Position)
+}
+
+// examples prints the example functions of the symbol, which are named like the go test tool expects them.
+func (w *printer) examples(symbol string) {
+	prefix := "Example" + symbol
+
+	var names []string
+	for _, name := range sortedKeys(w.p.Functions) {
+		suffix, ok := strings.CutPrefix(name, prefix)
+		if !ok || !test(w.p.Functions[name]) {
+			continue
+		}
+
+		// suffixes start with an underscore and a lower case letter, like ExampleT_suffix
+		if r, _ := utf8.DecodeRuneInString(strings.TrimPrefix(suffix, "_")); suffix == "" || strings.HasPrefix(suffix, "_") && unicode.IsLower(r) {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return
+	}
+
+	w.heading("EXAMPLES")
+	for _, name := range names {
+		fn := w.p.Functions[name]
+		src := fn.Body
+		if src == "" {
+			src = funcDecl(fn, nil)
+		}
+
+		w.line(w.style("1", name))
+		for _, line := range strings.Split(w.code(src), "\n") {
+			w.line(indent + line)
+		}
+		w.line("")
+	}
+}
+
+// references summarizes the usages of a type by kind and lists them.
+func (w *printer) references(s *api.Struct) {
+	if len(s.ReferencedBy) == 0 {
+		return
+	}
+
+	counts := map[api.Stereotype]int{}
+	for _, r := range s.ReferencedBy {
+		counts[r.Kind]++
+	}
+
+	var kinds []string
+	for _, k := range sortedKeys(counts) {
+		kinds = append(kinds, fmt.Sprintf("%d %s", counts[k], k))
+	}
+
+	noun := "references"
+	if len(s.ReferencedBy) == 1 {
+		noun = "reference"
+	}
+
+	w.heading("REFERENCES")
+	w.line(fmt.Sprintf("%s%d %s: %s", indent, len(s.ReferencedBy), noun, strings.Join(kinds, ", ")))
+	for _, r := range s.ReferencedBy {
+		w.line(indent + strings.TrimSpace(w.ref(r.From)+" "+string(r.Kind)+" "+r.Name))
+	}
+	w.line("")
+}
+
+// section prints a heading followed by highlighted declarations, if there are any.
+func (w *printer) section(title string, decls []string) {
+	if len(decls) == 0 {
+		return
+	}
+
+	w.heading(title)
+	for _, decl := range decls {
+		w.line(w.code(decl))
+	}
+	w.line("")
+}
+
+// list prints a heading followed by already formatted entries, if there are any.
+func (w *printer) list(title string, entries []string) {
+	if len(entries) == 0 {
+		return
+	}
+
+	w.heading(title)
+	for _, e := range entries {
+		w.line(indent + e)
+	}
+	w.line("")
+}
+
+func (w *printer) heading(title string) {
+	w.line(w.style("1", title))
+}
+
+func (w *printer) deprecated(d *api.Deprecation) {
+	if d == nil {
+		return
+	}
+
+	notice := "Deprecated"
+	if d.Since != "" {
+		notice += " since " + d.Since
+	}
+	w.line(w.style("31", notice+": "+d.Notice))
+}
+
+// position prints the location of the declaration, linked to its source.
+func (w *printer) position(p api.Position) {
+	if !p.Valid() {
+		return
+	}
+
+	w.line(w.style("90", "Defined at "+w.link(p.URL, p.String())))
+	w.line("")
+}
+
+// doc prints a comment with resolved cross references as hyperlinks. Indented lines are highlighted as code.
+func (w *printer) doc(comment, prefix string) {
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return
+	}
+
+	for _, line := range strings.Split(comment, "\n") {
+		if strings.HasPrefix(line, "\t") {
+			w.line(prefix + w.code(text(line)))
+			continue
+		}
+
+		var sb strings.Builder
+		last := 0
+		for _, m := range xref.FindAllStringSubmatchIndex(line, -1) {
+			sb.WriteString(line[last:m[0]])
+			sb.WriteString(w.link(w.urls[line[m[2]:m[3]]], w.style("4", line[m[4]:m[5]])))
+			last = m[1]
+		}
+		sb.WriteString(line[last:])
+		w.line(strings.TrimRight(prefix+sb.String(), " "))
+	}
+	w.line("")
+}
+
+// code highlights Go source and links the identifiers of the types declared in the module.
+func (w *printer) code(src string) string {
+	if w.opts.Plain {
+		return src
+	}
+
+	tokens := highlight.Tokens(src)
+	var sb strings.Builder
+	for i, t := range tokens {
+		s := highlight.ANSITokens([]highlight.Token{t})
+		if t.Class == "" && token.IsIdentifier(t.Text) {
+			var ref *api.Struct
+			if i >= 2 && tokens[i-1].Text == "." {
+				ref = w.qualified(tokens[i-2].Text, t.Text)
+			} else {
+				ref = w.p.Structs[t.Text]
+			}
+
+			if ref != nil {
+				s = w.link(w.urls[w.m.Anchor(ref.TypeDefinition)], s)
+			}
+		}
+		sb.WriteString(s)
+	}
+	return sb.String()
+}
+
+// qualified returns the type of another package of the module.
+func (w *printer) qualified(pkg, name string) *api.Struct {
+	for _, path := range sortedKeys(w.m.Packages) {
+		if p := w.m.Packages[path]; p.Name == pkg && p.Structs[name] != nil {
+			return p.Structs[name]
+		}
+	}
+	return nil
+}
+
+func (w *printer) lookupStruct(td *api.TypeDesc) *api.Struct {
+	if td == nil {
+		return nil
+	}
+
+	p := w.m.Packages[td.TypeDefinition.ImportPath]
+	if p == nil {
+		return nil
+	}
+	return p.Structs[td.TypeDefinition.Identifier]
+}
+
+// ref formats a reference qualified by its package, if it is declared in another one, and links it.
+func (w *printer) ref(ref api.RefId) string {
+	name := ref.Identifier
+	if ref.ImportPath != w.p.PackageDefinition.ImportPath {
+		name = ref.PackageName() + "." + name
+	}
+	return w.link(w.urls[w.m.Anchor(ref)], name)
+}
+
+// link wraps the text into an OSC 8 hyperlink, which most terminals support.
+func (w *printer) link(url, text string) string {
+	if w.opts.Plain || url == "" {
+		return text
+	}
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// style applies the select graphic rendition parameters to the text.
+func (w *printer) style(sgr, text string) string {
+	if w.opts.Plain {
+		return text
+	}
+	return "\x1b[" + sgr + "m" + text + "\x1b[0m"
+}
+
+func (w *printer) line(s string) {
+	w.sb.WriteString(s + "\n")
+}
+
+// test reports, whether the function is declared in a test file, like examples are.
+func test(fn *api.Function) bool {
+	return strings.HasSuffix(fn.Position.File, "_test.go")
+}
+
+func field(s *api.Struct, name string) *api.Field {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func kind(s *api.Struct) string {
+	switch {
+	case s.Interface():
+		return "interface"
+	case len(s.Stereotypes) > 0:
+		return "struct"
+	default:
+		return ""
+	}
+}
+
+func constDecl(c api.Constant) string {
+	decl := "const " + c.RefId.Identifier
+	if c.Expr != "" {
+		decl += " = " + c.Expr
+	}
+	return decl
+}
+
+func varDecl(v *api.Variable) string {
+	return strings.TrimSpace("var " + v.Name + " " + typeString(v.TypeDesc))
+}
+
+func funcDecl(fn *api.Function, recv *api.Recv) string {
+	decl := "func "
+	if recv != nil {
+		decl += "(" + strings.TrimSpace(recv.Name+" "+recv.TypeString) + ") "
+	}
+	return decl + fn.Signature
+}
+
+// typeDecl renders the type declaration including the fields of structs and the methods of interfaces.
+func typeDecl(s *api.Struct) string {
+	var sb strings.Builder
+	sb.WriteString("type " + s.Name)
+	if len(s.Generics) > 0 {
+		var params []string
+		for _, g := range s.Generics {
+			params = append(params, g.Name+" "+typeString(g.TypeDesc))
+		}
+		sb.WriteString("[" + strings.Join(params, ", ") + "]")
+	}
+
+	switch {
+	case s.Interface():
+		sb.WriteString(" interface {\n")
+		for _, method := range s.Methods {
+			sb.WriteString("\t" + method.Signature + "\n")
+		}
+		sb.WriteString("}")
+	case len(s.Stereotypes) > 0:
+		sb.WriteString(" struct {\n")
+		for _, f := range s.Fields {
+			sb.WriteString("\t" + strings.TrimSpace(f.Name+" "+typeString(f.TypeDesc)))
+			if c := strings.TrimSpace(f.Comment); c != "" {
+				sb.WriteString(" // " + strings.ReplaceAll(text(c), "\n", " "))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("}")
+	}
+
+	return sb.String()
+}
+
+// gofmt aligns the fields and comments of a declaration like the source. Invalid declarations are kept as they are.
+func gofmt(decl string) string {
+	buf, err := format.Source([]byte(decl))
+	if err != nil {
+		return decl
+	}
+	return string(buf)
+}
+
+func typeString(td *api.TypeDesc) string {
+	if td == nil {
+		return ""
+	}
+	return td.SrcTypeDefinition
+}
+
+// text removes the markup of resolved cross references.
+func text(s string) string {
+	return xref.ReplaceAllString(s, "$2")
+}
+
+func sortedMethods(methods []*api.Method) []*api.Method {
+	res := append([]*api.Method{}, methods...)
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+func sortedKeys[K ~string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
@@ -0,0 +1,45 @@
+package terminal_test
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/fixture"
+	"github.com/worldiety/gdoc/internal/generator/terminal"
+)
+
+func ExampleShow() {
+	m, err := fixture.Resolve()
+	if err != nil {
+		panic(err)
+	}
+
+	for _, query := range []string{"store.NewStore", "store.Store"} {
+		s, err := terminal.Show(m, query, terminal.Options{Plain: true})
+		if err != nil {
+			panic(err)
+		}
+		fmt.Print(s)
+	}
+	// Output:
+	// func NewStore(mode Mode) *Store
+	//     NewStore creates an empty Store.
+	//
+	// Defined at store/store.go:14:6
+	//
+	// EXAMPLES
+	// ExampleNewStore
+	//     func ExampleNewStore()
+	//
+	// type Store struct {
+	// 	Mode Mode // controls the writes
+	// }
+	//     Store keeps the entities in memory.
+	//
+	// CONSTRUCTORS
+	// func NewStore(mode Mode) *Store
+	//
+	// REFERENCES
+	//     1 reference: 1 result
+	//     NewStore result
+	//
+	// Defined at store/store.go:9:6
+}
@@ -0,0 +1,99 @@
+// Package git wraps the local git binary, which is used to inspect other revisions of a module.
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"golang.org/x/mod/semver"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Run executes git with the given arguments in dir and returns the trimmed standard output.
+func Run(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// TopLevel returns the root directory of the repository, which contains dir.
+func TopLevel(dir string) (string, error) {
+	return Run(dir, "rev-parse", "--show-toplevel")
+}
+
+// Worktree checks out the given ref into a temporary, detached worktree of the repository containing dir.
+// It returns the directory within the worktree, which corresponds to dir, and a function to remove the worktree again.
+func Worktree(dir, ref string) (string, func() error, error) {
+	top, err := TopLevel(dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// git reports the top level with resolved symlinks
+	if abs, err := filepath.Abs(dir); err == nil {
+		if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+			dir = resolved
+		}
+	}
+
+	rel, err := filepath.Rel(top, dir)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot relate %s to %s: %w", dir, top, err)
+	}
+
+	// resolve the ref first, so that it cannot be taken for an option of git worktree
+	commit, err := Run(top, "rev-parse", "--verify", "--end-of-options", ref+"^{commit}")
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid ref %s: %w", ref, err)
+	}
+
+	tmp, err := os.MkdirTemp("", "gdoc-worktree-")
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot create worktree dir: %w", err)
+	}
+
+	if _, err := Run(top, "worktree", "add", "--detach", "--force", tmp, commit); err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", nil, err
+	}
+
+	remove := func() error {
+		if _, err := Run(top, "worktree", "remove", "--force", tmp); err != nil {
+			return err
+		}
+		return os.RemoveAll(tmp)
+	}
+
+	return filepath.Join(tmp, rel), remove, nil
+}
+
+// Tags returns all semantic version tags of the repository containing dir, sorted from oldest to newest.
+func Tags(dir string) ([]string, error) {
+	out, err := Run(dir, "tag", "--list")
+	if err != nil {
+		return nil, err
+	}
+
+	var res []string
+	for _, tag := range strings.Fields(out) {
+		if semver.IsValid(tag) {
+			res = append(res, tag)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return semver.Compare(res[i], res[j]) < 0
+	})
+
+	return res, nil
+}
@@ -0,0 +1,168 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Repository walks up from dir to the working tree root, which contains the .git directory or, for linked
+// worktrees and submodules, the .git file. It returns the root and the resolved git directory.
+func Repository(dir string) (root, gitDir string, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	for {
+		candidate := filepath.Join(dir, ".git")
+		info, err := os.Stat(candidate)
+		if err == nil {
+			if info.IsDir() {
+				return dir, candidate, nil
+			}
+
+			buf, err := os.ReadFile(candidate)
+			if err != nil {
+				return "", "", err
+			}
+
+			target, ok := strings.CutPrefix(strings.TrimSpace(string(buf)), "gitdir:")
+			if !ok {
+				return "", "", fmt.Errorf("invalid .git file in %s", dir)
+			}
+
+			target = strings.TrimSpace(target)
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(dir, target)
+			}
+			return dir, target, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", fmt.Errorf("not a git repository: %s", dir)
+		}
+		dir = parent
+	}
+}
+
+// Head reads the commit hash of the checked out revision from the git directory of the repository, which
+// contains dir. It does not require the git binary.
+func Head(dir string) (string, error) {
+	_, gitDir, err := Repository(dir)
+	if err != nil {
+		return "", err
+	}
+
+	buf, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return "", fmt.Errorf("cannot read HEAD: %w", err)
+	}
+
+	head := strings.TrimSpace(string(buf))
+	ref, ok := strings.CutPrefix(head, "ref:")
+	if !ok {
+		// a detached HEAD contains the hash itself
+		return head, nil
+	}
+
+	return resolveRef(commonDir(gitDir), strings.TrimSpace(ref))
+}
+
+// commonDir returns the directory, which contains the refs shared by all worktrees.
+func commonDir(gitDir string) string {
+	buf, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return gitDir
+	}
+
+	dir := strings.TrimSpace(string(buf))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(gitDir, dir)
+	}
+	return dir
+}
+
+// resolveRef reads a ref like refs/heads/main either as loose file or from the packed-refs.
+func resolveRef(gitDir, ref string) (string, error) {
+	if buf, err := os.ReadFile(filepath.Join(gitDir, filepath.FromSlash(ref))); err == nil {
+		return strings.TrimSpace(string(buf)), nil
+	}
+
+	file, err := os.Open(filepath.Join(gitDir, "packed-refs"))
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve %s: %w", ref, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		hash, name, ok := strings.Cut(scanner.Text(), " ")
+		if ok && name == ref {
+			return hash, nil
+		}
+	}
+
+	return "", fmt.Errorf("cannot resolve %s", ref)
+}
+
+// RemoteURL reads the url of the named remote from the config of the repository, which contains dir.
+func RemoteURL(dir, remote string) (string, error) {
+	_, gitDir, err := Repository(dir)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Open(filepath.Join(commonDir(gitDir), "config"))
+	if err != nil {
+		return "", fmt.Errorf("cannot read git config: %w", err)
+	}
+	defer file.Close()
+
+	section := `[remote "` + remote + `"]`
+	var inSection bool
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inSection = line == section
+			continue
+		}
+
+		if key, value, ok := strings.Cut(line, "="); ok && inSection && strings.TrimSpace(key) == "url" {
+			return strings.TrimSpace(value), nil
+		}
+	}
+
+	return "", fmt.Errorf("remote %s is not configured", remote)
+}
+
+// WebURL converts the url of a remote like git@github.com:owner/repo.git into the web url of the repository
+// like https://github.com/owner/repo.
+func WebURL(remote string) string {
+	url := strings.TrimSuffix(strings.TrimSuffix(remote, "/"), ".git")
+	if rest, ok := strings.CutPrefix(url, "git@"); ok {
+		host, path, _ := strings.Cut(rest, ":")
+		return "https://" + host + "/" + path
+	}
+
+	if rest, ok := strings.CutPrefix(url, "ssh://"); ok {
+		rest = rest[strings.Index(rest, "@")+1:]
+		host, path, _ := strings.Cut(rest, "/")
+		host, _, _ = strings.Cut(host, ":") // drop the ssh port
+		return "https://" + host + "/" + path
+	}
+
+	// drop credentials of http remotes
+	if scheme, rest, ok := strings.Cut(url, "://"); ok {
+		if at := strings.Index(rest, "@"); at >= 0 && at < strings.Index(rest+"/", "/") {
+			rest = rest[at+1:]
+		}
+		return scheme + "://" + rest
+	}
+
+	return url
+}
@@ -0,0 +1,104 @@
+package highlight
+
+import (
+	"html"
+	"strings"
+)
+
+// ansiColors are the select graphic rendition parameters of each class.
+var ansiColors = map[Class]string{
+	Keyword:      "35",
+	Builtin:      "36",
+	String:       "32",
+	Number:       "33",
+	Comment:      "90",
+	FunctionDecl: "1;34",
+	FunctionCall: "34",
+	LineNumber:   "90",
+}
+
+// Asciidoc returns the snippet with a role for each classified token. All texts are passed through, so that no
+// further substitutions like replacements or attribute references apply. Listings must enable the quotes and
+// macros substitutions.
+func Asciidoc(src string) string {
+	return AsciidocTokens(Tokens(src))
+}
+
+// AsciidocTokens formats already classified tokens like Asciidoc. Adjacent unclassified tokens share a single
+// passthrough.
+func AsciidocTokens(tokens []Token) string {
+	var sb, plain strings.Builder
+	flush := func() {
+		if text := plain.String(); strings.TrimSpace(text) != "" {
+			sb.WriteString(pass(text))
+		} else {
+			sb.WriteString(text)
+		}
+		plain.Reset()
+	}
+
+	for _, t := range tokens {
+		if t.Class == "" {
+			plain.WriteString(t.Text)
+			continue
+		}
+
+		flush()
+		sb.WriteString("[" + string(t.Class) + "]##" + pass(t.Text) + "##")
+	}
+	flush()
+
+	return sb.String()
+}
+
+// pass returns the text as inline passthrough, which only escapes the special characters.
+func pass(s string) string {
+	return "pass:c[" + strings.ReplaceAll(s, "]", `\]`) + "]"
+}
+
+// HTML returns the escaped snippet with a span for each classified token.
+func HTML(src string) string {
+	return HTMLTokens(Tokens(src))
+}
+
+// HTMLTokens formats already classified tokens like HTML.
+func HTMLTokens(tokens []Token) string {
+	var sb strings.Builder
+	for _, t := range tokens {
+		if t.Class == "" {
+			sb.WriteString(html.EscapeString(t.Text))
+			continue
+		}
+
+		sb.WriteString(`<span class="` + string(t.Class) + `">` + html.EscapeString(t.Text) + `</span>`)
+	}
+	return sb.String()
+}
+
+// ANSI returns the snippet colored for terminals.
+func ANSI(src string) string {
+	return ANSITokens(Tokens(src))
+}
+
+// ANSITokens formats already classified tokens like ANSI. Colors are reset at each line break, so that pagers
+// showing only a part of the snippet stay readable.
+func ANSITokens(tokens []Token) string {
+	var sb strings.Builder
+	for _, t := range tokens {
+		color, ok := ansiColors[t.Class]
+		if !ok {
+			sb.WriteString(t.Text)
+			continue
+		}
+
+		for i, line := range strings.Split(t.Text, "\n") {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			if line != "" {
+				sb.WriteString("\x1b[" + color + "m" + line + "\x1b[0m")
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,184 @@
+// Package highlight colors Go snippets without any external highlighter. The classes equal the roles of the
+// docinfo.html stylesheet, so the output of all generators looks alike.
+package highlight
+
+import (
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// Class is the syntactic category of a token. Whitespace and punctuation have no class.
+type Class string
+
+const (
+	Keyword      Class = "keyword"
+	Builtin      Class = "builtin"
+	String       Class = "string"
+	Number       Class = "number"
+	Comment      Class = "codeBlockComment"
+	FunctionDecl Class = "functionDecl"
+	FunctionCall Class = "functionCall"
+	Operator     Class = "operator"
+	LineNumber   Class = "lineNumber"
+)
+
+// Token is a part of a snippet. Concatenating the texts of all tokens yields the snippet again.
+type Token struct {
+	Class Class
+	Text  string
+}
+
+// Tokens splits the snippet into classified tokens. Snippets need not be complete files, and invalid input is
+// kept as unclassified text.
+func Tokens(src string) []Token {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+
+	type lexeme struct {
+		offset int
+		tok    token.Token
+		text   string
+	}
+
+	var lexemes []lexeme
+	var s scanner.Scanner
+	s.Init(file, []byte(src), func(token.Position, string) {}, scanner.ScanComments)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+
+		// skip the automatically inserted semicolons, which have no text
+		if tok == token.SEMICOLON && lit != ";" {
+			continue
+		}
+
+		text := lit
+		if text == "" {
+			text = tok.String()
+		}
+
+		offset := file.Offset(pos)
+		switch {
+		case tok == token.COMMENT:
+			// comments and raw strings are reported without carriage returns, so take the text from the source
+			text = src[offset : offset+commentLen(src[offset:])]
+		case tok == token.STRING && strings.HasPrefix(text, "`"):
+			if i := strings.IndexByte(src[offset+1:], '`'); i >= 0 {
+				text = src[offset : offset+i+2]
+			}
+		}
+
+		lexemes = append(lexemes, lexeme{offset: offset, tok: tok, text: text})
+	}
+
+	var res []Token
+	last := 0
+	inDecl := false // between a func keyword and the name of the declared function
+	depth := 0      // the parenthesis depth of a receiver
+	for i, l := range lexemes {
+		if l.offset < last {
+			continue
+		}
+
+		if l.offset > last {
+			res = append(res, Token{Text: src[last:l.offset]})
+		}
+
+		next := token.ILLEGAL
+		if i+1 < len(lexemes) {
+			next = lexemes[i+1].tok
+		}
+
+		var class Class
+		switch {
+		case l.tok == token.FUNC:
+			class = Keyword
+			// a receiver follows only at the start of a declaration, otherwise it is a function literal or type
+			inDecl = next != token.LPAREN || i == 0 || lexemes[i-1].tok == token.SEMICOLON || lexemes[i-1].tok == token.RBRACE
+		case l.tok.IsKeyword():
+			class = Keyword
+		case l.tok == token.IDENT && next == token.LPAREN && inDecl && depth == 0:
+			class = FunctionDecl
+			inDecl = false
+		case l.tok == token.IDENT && types.Universe.Lookup(l.text) != nil:
+			class = Builtin
+		case l.tok == token.IDENT && next == token.LPAREN:
+			class = FunctionCall
+		case l.tok == token.INT, l.tok == token.FLOAT, l.tok == token.IMAG:
+			class = Number
+		case l.tok == token.STRING, l.tok == token.CHAR:
+			class = String
+		case l.tok == token.COMMENT:
+			class = Comment
+		case l.tok == token.LPAREN:
+			if inDecl {
+				depth++
+			}
+		case l.tok == token.RPAREN:
+			if inDecl && depth > 0 {
+				depth--
+			}
+		case l.tok.IsOperator() && !punctuation(l.tok):
+			class = Operator
+		}
+
+		res = append(res, Token{Class: class, Text: l.text})
+		last = l.offset + len(l.text)
+	}
+
+	if last < len(src) {
+		res = append(res, Token{Text: src[last:]})
+	}
+
+	return res
+}
+
+// commentLen returns the length of the comment at the start of s.
+func commentLen(s string) int {
+	end := "\n"
+	if strings.HasPrefix(s, "/*") {
+		end = "*/"
+	}
+
+	i := strings.Index(s, end)
+	switch {
+	case i < 0:
+		return len(s)
+	case end == "*/":
+		return i + len(end)
+	default:
+		return i
+	}
+}
+
+// punctuation reports, whether the token only structures the code.
+func punctuation(tok token.Token) bool {
+	switch tok {
+	case token.LPAREN, token.RPAREN, token.LBRACK, token.RBRACK, token.LBRACE, token.RBRACE,
+		token.COMMA, token.PERIOD, token.SEMICOLON, token.COLON:
+		return true
+	default:
+		return false
+	}
+}
+
+// Lines splits the tokens at line breaks, so that each line can be rendered on its own. The line breaks are not
+// part of the result.
+func Lines(tokens []Token) [][]Token {
+	res := [][]Token{nil}
+	for _, t := range tokens {
+		for i, part := range strings.Split(t.Text, "\n") {
+			if i > 0 {
+				res = append(res, nil)
+			}
+			if part != "" {
+				res[len(res)-1] = append(res[len(res)-1], Token{Class: t.Class, Text: part})
+			}
+		}
+	}
+	return res
+}
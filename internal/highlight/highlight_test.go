@@ -0,0 +1,27 @@
+package highlight
+
+import "fmt"
+
+func ExampleTokens() {
+	for _, t := range Tokens(`func (r *T) Len() int { return len(r.s) + 1 } // size`) {
+		if t.Class != "" {
+			fmt.Printf("%s %s\n", t.Class, t.Text)
+		}
+	}
+	// Output:
+	// keyword func
+	// operator *
+	// functionDecl Len
+	// builtin int
+	// keyword return
+	// builtin len
+	// operator +
+	// number 1
+	// codeBlockComment // size
+}
+
+func ExampleHTML() {
+	fmt.Println(HTML(`fmt.Println("a < b")`))
+	// Output:
+	// fmt.<span class="functionCall">Println</span>(<span class="string">&#34;a &lt; b&#34;</span>)
+}
@@ -2,29 +2,36 @@ package golang
 
 import (
 	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/highlight"
+	"strconv"
 	"strings"
 )
 
 const (
-	preservedLinebreak = " +\n"
-	plusSuffix         = " +"
-	simpleLinebreak    = "\n"
-	codeBlockDelimiter = "****"
-	codeBlockName      = "[.code]"
-	mono               = "[mono]"
-	passPrefix         = "pass:"
-	commentPrefix      = "//"
-	hash               = "#"
-	boldDelimiter      = "**"
-	italicDelimiter    = "__"
-	ws                 = " "
-	tab                = "\t"
-	dot                = "."
-	hyphen             = "-"
-	asterisk           = "*"
-	comma              = ","
-	equals             = "="
-	nbsp               = "{nbsp}"
+	preservedLinebreak   = " +\n"
+	plusSuffix           = " +"
+	simpleLinebreak      = "\n"
+	codeBlockDelimiter   = "****"
+	listingDelimiter     = "----"
+	exampleDelimiter     = "===="
+	passthroughDelimiter = "++++"
+	tableDelimiter       = "|==="
+	codeBlockName        = "[.code]"
+	mono                 = "[mono]"
+	passPrefix           = "pass:"
+	commentPrefix        = "//"
+	hash                 = "#"
+	boldDelimiter        = "**"
+	italicDelimiter      = "__"
+	ws                   = " "
+	tab                  = "\t"
+	dot                  = "."
+	hyphen               = "-"
+	asterisk             = "*"
+	comma                = ","
+	equals               = "="
+	nbsp                 = "{nbsp}"
 )
 
 type bracketType int
@@ -57,16 +64,50 @@ func operatorFormat(s string) string {
 	return fmt.Sprintf("%s%s", enclosingBrackets(square, operator), enclose(hash, s))
 }
 
-func readme(s string, n int) string {
-	return fmt.Sprintf("%s%s%s%s%s%s", simpleLinebreaks(2), lvl(n), ws, bold(italic(readmeTitle)), simpleLinebreak, s)
-}
-
 func codeBlock(s string) string {
 	return fmt.Sprintf("%s%s%s%s%s%s%s%s%s",
 		simpleLinebreak, codeBlockName, simpleLinebreak, codeBlockDelimiter,
 		simpleLinebreak, s, simpleLinebreak, codeBlockDelimiter, simpleLinebreak)
 }
 
+// sourceListing renders the highlighted source of a declaration as collapsible block, whose line numbers start at
+// the given line of its file.
+func sourceListing(body string, line int) string {
+	if body == "" {
+		return ""
+	}
+
+	lines := highlight.Lines(highlight.Tokens(body))
+	width := len(strconv.Itoa(line + len(lines) - 1))
+	var listing []string
+	for i, tokens := range lines {
+		number := highlight.Token{Class: highlight.LineNumber, Text: fmt.Sprintf("%*d", width, line+i)}
+		listing = append(listing, highlight.AsciidocTokens(append([]highlight.Token{number, {Text: ws}}, tokens...)))
+	}
+
+	return simpleLinebreaks(2) + strings.Join([]string{
+		dot + bodyTitle,
+		enclosingBrackets(square, "%collapsible"),
+		exampleDelimiter,
+		enclosingBrackets(square, `.source,subs="quotes,macros"`),
+		listingDelimiter,
+		strings.Join(listing, simpleLinebreak),
+		listingDelimiter,
+		exampleDelimiter,
+	}, simpleLinebreak) + simpleLinebreak
+}
+
+// highlightCode highlights a line of code in a comment, keeping its resolved cross references.
+func highlightCode(s string) string {
+	var res string
+	last := 0
+	for _, m := range crossReference.FindAllStringIndex(s, -1) {
+		res += highlight.Asciidoc(s[last:m[0]]) + s[m[0]:m[1]]
+		last = m[1]
+	}
+	return res + highlight.Asciidoc(s[last:])
+}
+
 func passThrough(s string) string {
 	return fmt.Sprintf("%s%s", passPrefix, enclosingBrackets(square, s))
 }
@@ -125,6 +166,47 @@ func lvl(n int) (lvlStr string) {
 	return
 }
 
+// since formats the release, which first declared an element, or returns nothing, if it is unknown.
+func since(version string) string {
+	if version == "" {
+		return ""
+	}
+	return ws + enclosingBrackets(square, sinceRole) + enclose(hash, sinceNotice+ws+version)
+}
+
+// deprecated flags a deprecated element together with the release, which deprecated it, if it is known.
+func deprecated(d *api.Deprecation) string {
+	if d == nil {
+		return ""
+	}
+
+	notice := deprecatedNotice
+	if d.Since != "" {
+		notice += ws + strings.ToLower(sinceNotice) + ws + d.Since
+	}
+	return ws + enclosingBrackets(square, deprecatedRole) + enclose(hash, notice)
+}
+
+// source links the declaration at the given position to its source, if its url is known.
+func source(p api.Position) string {
+	if p.URL == "" {
+		return ""
+	}
+	return ws + "link:" + p.URL + enclosingBrackets(square, sourceNotice+comma+"role="+sourceRole)
+}
+
+// struck adds the line-through role to a formatted name like nameFormat returns it, if the element is deprecated.
+func struck(formatted string, d *api.Deprecation) string {
+	if d == nil {
+		return formatted
+	}
+
+	if role, text, ok := strings.Cut(strings.TrimPrefix(formatted, "["), "]"); ok && strings.HasPrefix(formatted, "[") {
+		return enclosingBrackets(square, role+ws+lineThroughRole) + text
+	}
+	return enclosingBrackets(square, lineThroughRole) + enclose(hash, formatted)
+}
+
 func bold(s ...string) string {
 	return enclose(boldDelimiter, s...)
 }
@@ -263,7 +345,7 @@ func handleIndentedBlock(current string, tmpList []string) ([]string, bool) {
 			count++
 			continue
 		}
-		current = trimAllPrefixWSAndTabs(current)
+		current = highlightCode(trimAllPrefixWSAndTabs(current))
 		for i := 0; i < count; i++ {
 			current = nbsp + current
 		}
@@ -27,9 +27,14 @@ const (
 	typ3             = "type"
 	nam3             = "name"
 	info             = "information"
+	sinceRole        = "since"
+	deprecatedRole   = "deprecated"
+	lineThroughRole  = "line-through"
+	sourceRole       = "source"
 	code             = "code"
 	funcTitle        = "func"
 	structTitle      = "struct"
+	interfaceTitle   = "interface"
 	mapPrefix        = "map"
 )
 
@@ -37,38 +42,31 @@ func (m AModule) AnchorID() string {
 	return enclosingDoubleBrackets(square, m.Name)
 }
 
-func (m AModule) title() string {
-	return title(moduleTitlePrefix, m.Name, "", 1)
-}
-
-func (m AModule) readme() string {
-	if m.Readme != "" {
-		return readme(m.Readme, 2)
-	}
-	return ""
-}
-
 func (p APackage) AnchorID() string {
-	return enclosingDoubleBrackets(square, p.Name)
-}
-
-func (p APackage) title() string {
-	return title(keywordFormat(packageTitlePrefix), p.AnchorID(), nameFormat(p.Name), 2)
-}
-
-func (p APackage) readme() string {
-	if p.Readme != "" {
-		return readme(p.Readme, 3)
-	}
-	return ""
+	return enclosingDoubleBrackets(square, p.anchor.of(p.PackageDefinition.Package()))
 }
 
 func (s AStruct) asciidocFormattedSigOpen() string {
+	kind := structTitle
+	if s.Interface() {
+		kind = interfaceTitle
+	}
 
 	return fmt.Sprintf("%s%s%s%s%s%s%s%s%s%s%s%s%s",
-		enclosingBrackets(square, keyword), enclose(hash, typ3), ws, enclosingDoubleBrackets(square, s.TypeDefinition.ID()),
+		enclosingBrackets(square, keyword), enclose(hash, typ3), ws, enclosingDoubleBrackets(square, s.anchor.of(s.TypeDefinition)),
 		enclosingBrackets(square, str1ng), enclose(hash, s.Name), s.generics().String(), ws, enclosingBrackets(square, keyword),
-		enclose(hash, structTitle), ws, operatorFormat("{"), preservedLinebreak)
+		enclose(hash, kind), ws, operatorFormat("{"), preservedLinebreak)
+}
+
+// asciidocFormattedInterfaceMethods lists the method set of an interface within its type declaration
+func (s AStruct) asciidocFormattedInterfaceMethods() string {
+	var str string
+	for _, m := range s.AMethods() {
+		fn := m.function()
+		str += indent(fmt.Sprintf("%s%s%s%s", nameFormat(fn.Name), enclosingBrackets(round, fn.asciidocFormattedParameters()),
+			ws, fn.asciidocFormattedResults()), 2) + preservedLinebreak
+	}
+	return str
 }
 
 func (s AStruct) asciidocFormattedSigClose() string {
@@ -105,7 +103,7 @@ func (f AField) asciidocFormattedType() string {
 }
 
 func (v AVariable) asciidocFormattedType() string {
-	f := NewAField(*api.NewField(v.Name, v.Comment, v.Doc, v.TypeDesc, nil))
+	f := NewAField(*api.NewField(v.Name, v.Comment, v.Doc, v.TypeDesc, nil), v.anchor)
 	return f.asciidocFormattedType()
 }
 
@@ -143,7 +141,7 @@ func (fn AFunction) asciidocFormattedParameters() string {
 	var s string
 	var c int
 	for _, p := range fn.Parameters {
-		s += NewAField(*p).String()
+		s += NewAField(*p, fn.anchor).String()
 		if c < len(fn.Parameters)-1 {
 			s = addComma(s)
 		}
@@ -157,7 +155,7 @@ func (fn AFunction) asciidocFormattedResults() string {
 	var s string
 	var c int
 	for _, r := range fn.Results {
-		results += NewAField(*r).String()
+		results += NewAField(*r, fn.anchor).String()
 		if c < len(fn.Results)-1 {
 			results = addComma(results)
 		}
@@ -177,8 +175,8 @@ func (f AField) asciidocFormattedMapType() string {
 	srcTypeDef := f.TypeDesc.SrcTypeDefinition
 	keyType := *f.TypeDesc.MapType.KeyType
 	valueType := *f.TypeDesc.MapType.ValueType
-	formattedKeySrcTypeDef := NewATypeDesc(keyType).typeString()
-	formattedValueSrcTypeDef := NewATypeDesc(valueType).typeString()
+	formattedKeySrcTypeDef := NewATypeDesc(keyType, f.anchor).typeString()
+	formattedValueSrcTypeDef := NewATypeDesc(valueType, f.anchor).typeString()
 
 	srcTypeDef = strings.Replace(srcTypeDef, keyType.SrcTypeDefinition, formattedKeySrcTypeDef, 1)
 	srcTypeDef = strings.Replace(srcTypeDef, valueType.SrcTypeDefinition, formattedValueSrcTypeDef, 1)
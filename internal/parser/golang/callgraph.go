@@ -0,0 +1,185 @@
+package golang
+
+import (
+	"errors"
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"go/types"
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/cha"
+	"golang.org/x/tools/go/callgraph/vta"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+	"sort"
+)
+
+const (
+	CallGraphCHA = "cha" // class hierarchy analysis, sound but imprecise for dynamic calls
+	CallGraphVTA = "vta" // variable type analysis, refines the cha result
+)
+
+// ResolveCalls builds the SSA form of all module packages and computes the call graph with the given algorithm.
+// Each exported function and method gets the exported functions and methods of the module, which it calls or which
+// call it, up to the given depth. Unexported functions and closures are traversed but not recorded.
+func ResolveCalls(dir string, m *api.Module, depth int, algorithm string) error {
+	var paths []string
+	for path := range m.Packages {
+		paths = append(paths, path)
+	}
+
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo |
+			packages.NeedImports | packages.NeedDeps,
+		Dir: dir,
+	}, paths...)
+	if err != nil {
+		return fmt.Errorf("could not load packages from %s: %w", dir, err)
+	}
+
+	prog, ssaPkgs := ssautil.Packages(pkgs, ssa.InstantiateGenerics)
+	for i, p := range ssaPkgs {
+		if p == nil {
+			// the package has type errors, which would silently drop its calls from the graph
+			return fmt.Errorf("cannot build ssa of %s: %w", pkgs[i].PkgPath, packageError(pkgs[i]))
+		}
+	}
+	prog.Build()
+
+	var cg *callgraph.Graph
+	switch algorithm {
+	case CallGraphCHA:
+		cg = cha.CallGraph(prog)
+	case CallGraphVTA:
+		cg = vta.CallGraph(ssautil.AllFunctions(prog), cha.CallGraph(prog))
+	default:
+		return fmt.Errorf("unknown call graph algorithm: %s", algorithm)
+	}
+	cg.DeleteSyntheticNodes()
+
+	fns := map[api.RefId]*api.Function{}
+	for _, p := range m.Packages {
+		for _, fn := range p.Functions {
+			fns[fn.TypeDefinition] = fn
+		}
+		for _, s := range p.Structs {
+			for _, fn := range s.Constructors {
+				fns[fn.TypeDefinition] = fn
+			}
+			for _, method := range s.Methods {
+				fns[method.TypeDefinition] = method.Function
+			}
+		}
+	}
+
+	for node, ref := range exportedNodes(cg, m) {
+		fn := fns[ref]
+		if fn == nil {
+			continue
+		}
+
+		fn.Calls = reachable(node, depth, m, func(n *callgraph.Node) []*callgraph.Edge { return n.Out },
+			func(e *callgraph.Edge) *callgraph.Node { return e.Callee })
+		fn.CalledBy = reachable(node, depth, m, func(n *callgraph.Node) []*callgraph.Edge { return n.In },
+			func(e *callgraph.Edge) *callgraph.Node { return e.Caller })
+	}
+
+	return nil
+}
+
+// exportedNodes returns all call graph nodes, which represent an exported function or method of the module.
+func exportedNodes(cg *callgraph.Graph, m *api.Module) map[*callgraph.Node]api.RefId {
+	res := map[*callgraph.Node]api.RefId{}
+	for fn, node := range cg.Nodes {
+		if ref, ok := exportedRef(fn, m); ok {
+			res[node] = ref
+		}
+	}
+	return res
+}
+
+// exportedRef returns the identity of an exported function or method declared in the module.
+// Closures, wrappers and instantiations of generic functions are not considered.
+func exportedRef(fn *ssa.Function, m *api.Module) (api.RefId, bool) {
+	if fn == nil || fn.Pkg == nil || fn.Parent() != nil || fn.Synthetic != "" || fn.Object() == nil {
+		return api.RefId{}, false
+	}
+
+	path := fn.Pkg.Pkg.Path()
+	if _, ok := m.Packages[path]; !ok || !fn.Object().Exported() {
+		return api.RefId{}, false
+	}
+
+	recv := fn.Signature.Recv()
+	if recv == nil {
+		return api.NewRefID(path, fn.Name()), true
+	}
+
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+
+	named, ok := t.(*types.Named)
+	if !ok || !named.Obj().Exported() {
+		return api.RefId{}, false
+	}
+
+	return api.NewRefID(path, named.Obj().Name()+dot+fn.Name()), true
+}
+
+// reachable collects the exported functions of the module, which are reachable from the start node within the
+// given depth, either in call direction or against it.
+func reachable(start *callgraph.Node, depth int, m *api.Module, edges func(*callgraph.Node) []*callgraph.Edge, next func(*callgraph.Edge) *callgraph.Node) []api.CallRef {
+	visited := map[*callgraph.Node]bool{start: true}
+	found := map[api.RefId]int{}
+	current := []*callgraph.Node{start}
+	for level := 1; level <= depth && len(current) > 0; level++ {
+		var following []*callgraph.Node
+		for _, n := range current {
+			for _, e := range edges(n) {
+				other := next(e)
+				if visited[other] {
+					continue
+				}
+				visited[other] = true
+				following = append(following, other)
+
+				if ref, ok := exportedRef(other.Func, m); ok {
+					if _, ok := found[ref]; !ok {
+						found[ref] = level
+					}
+				}
+			}
+		}
+		current = following
+	}
+
+	res := make([]api.CallRef, 0, len(found))
+	for ref, d := range found {
+		res = append(res, api.CallRef{Target: ref, Depth: d})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Depth != res[j].Depth {
+			return res[i].Depth < res[j].Depth
+		}
+		return res[i].Target.ImportPath+dot+res[i].Target.Identifier < res[j].Target.ImportPath+dot+res[j].Target.Identifier
+	})
+
+	return res
+}
+
+// packageError returns the errors, which prevented loading the package.
+func packageError(pkg *packages.Package) error {
+	var errs []error
+	for _, err := range pkg.Errors {
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return fmt.Errorf("package is ill typed")
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,60 @@
+package golang
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/packages"
+)
+
+func ExampleResolveCalls() {
+	m, err := Parse("testdata/calls")
+	if err != nil {
+		panic(err)
+	}
+
+	fset := token.NewFileSet()
+	astPkgs, err := parser.ParseDir(fset, "testdata/calls/server", nil, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	var files []*ast.File
+	for _, f := range astPkgs["server"].Files {
+		files = append(files, f)
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	tpkg, err := conf.Check("example.com/calls/server", fset, files, nil)
+	if err != nil {
+		panic(err)
+	}
+	ResolvePackages(m, []*packages.Package{{Name: "server", PkgPath: "example.com/calls/server", Types: tpkg}})
+
+	if err := ResolveCalls("testdata/calls", m, 2, CallGraphCHA); err != nil {
+		panic(err)
+	}
+
+	// Server.Serve is called by Run through the unexported serve, thus with a depth of 2
+	p := m.Packages["example.com/calls/server"]
+	server := p.Structs["Server"]
+	for _, fn := range []*api.Function{p.Functions["Run"], server.Constructors[0], server.Methods[0].Function} {
+		fmt.Print(fn.Name, " calls")
+		for _, c := range fn.Calls {
+			fmt.Print(" ", c.Target.Identifier, ":", c.Depth)
+		}
+		fmt.Print(", called by")
+		for _, c := range fn.CalledBy {
+			fmt.Print(" ", c.Target.Identifier, ":", c.Depth)
+		}
+		fmt.Println()
+	}
+	// Output:
+	// Run calls NewServer:1 Server.Serve:2, called by
+	// NewServer calls, called by Run:1
+	// Serve calls, called by Run:2
+}
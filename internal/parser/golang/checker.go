@@ -0,0 +1,125 @@
+package golang
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/packages"
+	"path/filepath"
+	"strings"
+)
+
+// Checker type checks the packages of a module from source and keeps them, so that after a change only the changed
+// packages and the packages importing them are checked again, see Forget. In contrast to loading the changed
+// packages with Resolve, all packages share the same type universe. Like packages.Load, it ignores type errors, so
+// that a package, which is being edited, is still documented.
+type Checker struct {
+	dir      string // the root of the module
+	module   string // the module path
+	fset     *token.FileSet
+	std      types.ImporterFrom // imports all packages of other modules
+	checked  map[api.ImportPath]*types.Package
+	checking map[api.ImportPath]bool
+}
+
+// NewChecker returns a Checker for the module with the given path, which is located in dir.
+func NewChecker(dir, module string) *Checker {
+	fset := token.NewFileSet()
+	return &Checker{
+		dir:      dir,
+		module:   module,
+		fset:     fset,
+		std:      importer.ForCompiler(fset, "source", nil).(types.ImporterFrom),
+		checked:  map[api.ImportPath]*types.Package{},
+		checking: map[api.ImportPath]bool{},
+	}
+}
+
+// Packages type checks the packages of the module with the given import paths, unless they are already checked,
+// and returns them for ResolvePackages.
+func (c *Checker) Packages(paths ...api.ImportPath) ([]*packages.Package, error) {
+	var res []*packages.Package
+	for _, path := range paths {
+		pkg, err := c.Import(path)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, &packages.Package{Name: pkg.Name(), PkgPath: path, Types: pkg})
+	}
+
+	return res, nil
+}
+
+// Forget discards the given packages and all packages, which import them directly or indirectly, so that they are
+// checked again.
+func (c *Checker) Forget(paths ...api.ImportPath) {
+	stale := map[api.ImportPath]bool{}
+	for _, path := range paths {
+		stale[path] = true
+	}
+
+	for found := true; found; {
+		found = false
+		for path, pkg := range c.checked {
+			if stale[path] {
+				continue
+			}
+
+			for _, imp := range pkg.Imports() {
+				if stale[imp.Path()] {
+					stale[path] = true
+					found = true
+					break
+				}
+			}
+		}
+	}
+
+	for path := range stale {
+		delete(c.checked, path)
+	}
+}
+
+// Import implements types.Importer. The packages of the module are type checked by the Checker and all others by
+// the source importer of the standard library.
+func (c *Checker) Import(path string) (*types.Package, error) {
+	if path != c.module && !strings.HasPrefix(path, c.module+"/") {
+		return c.std.ImportFrom(path, c.dir, 0)
+	}
+
+	if pkg, ok := c.checked[path]; ok {
+		return pkg, nil
+	}
+
+	if c.checking[path] {
+		return nil, fmt.Errorf("import cycle through %s", path)
+	}
+	c.checking[path] = true
+	defer delete(c.checking, path)
+
+	dir := filepath.Join(c.dir, filepath.FromSlash(strings.TrimPrefix(path, c.module)))
+	bpkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list the files of %s: %w", path, err)
+	}
+
+	var files []*ast.File
+	for _, name := range bpkg.GoFiles {
+		file, err := parser.ParseFile(c.fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %s: %w", path, err)
+		}
+		files = append(files, file)
+	}
+
+	conf := types.Config{Importer: c, Error: func(err error) {}}
+	pkg, _ := conf.Check(path, c.fset, files, nil) // the type errors are ignored, see Checker
+	c.checked[path] = pkg
+	return pkg, nil
+}
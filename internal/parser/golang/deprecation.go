@@ -0,0 +1,288 @@
+package golang
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/packages"
+	"sort"
+	"strings"
+)
+
+// deprecatedPrefix starts the paragraph of a doc comment, which deprecates the declaration by Go convention.
+const deprecatedPrefix = "Deprecated:"
+
+// deprecation returns the Deprecated: paragraph of the given comments or nil. The paragraph must start a line
+// after an empty one, like gofmt and pkg.go.dev expect it.
+func deprecation(comments ...string) *api.Deprecation {
+	for _, comment := range comments {
+		for _, paragraph := range strings.Split(strings.ReplaceAll(comment, "\r\n", "\n"), "\n\n") {
+			paragraph = strings.TrimSpace(paragraph)
+			if notice, ok := strings.CutPrefix(paragraph, deprecatedPrefix); ok {
+				return &api.Deprecation{Notice: strings.Join(strings.Fields(notice), ws)}
+			}
+		}
+	}
+
+	return nil
+}
+
+// addDeprecations marks each declaration of the module, whose doc comment contains a Deprecated: paragraph.
+// Constants and variables inherit the paragraph of their group.
+func addDeprecations(m *api.Module) {
+	for _, p := range m.Packages {
+		p.Deprecated = deprecation(p.Doc)
+		if p.Deprecated != nil {
+			p.Stereotypes = append(p.Stereotypes, api.StereotypeDeprecated)
+		}
+
+		for _, block := range p.Consts {
+			for i := range block.Content {
+				block.Content[i].Deprecated = deprecation(block.Content[i].Comment, block.Doc)
+			}
+		}
+
+		for _, v := range p.Vars {
+			deprecateField((*api.Field)(v))
+		}
+
+		for _, fn := range p.Functions {
+			fn.Deprecated = deprecation(fn.Comment)
+		}
+
+		for _, s := range p.Structs {
+			s.Deprecated = deprecation(s.Comment)
+			if s.Deprecated != nil {
+				s.Stereotypes = append(s.Stereotypes, api.StereotypeDeprecated)
+			}
+
+			for _, f := range s.Fields {
+				deprecateField(f)
+			}
+			for _, fn := range s.Constructors {
+				fn.Deprecated = deprecation(fn.Comment)
+			}
+			for _, method := range s.Methods {
+				method.Deprecated = deprecation(method.Comment)
+			}
+		}
+	}
+}
+
+func deprecateField(f *api.Field) {
+	f.Deprecated = deprecation(f.Doc, f.Comment)
+	if f.Deprecated != nil {
+		f.Stereotypes = append(f.Stereotypes, api.StereotypeDeprecated)
+	}
+}
+
+// DeprecatedUsage is a reference to a deprecated declaration of the module from within the module.
+type DeprecatedUsage struct {
+	Target     api.RefId
+	Deprecated *api.Deprecation
+	Position   token.Position
+}
+
+func (u DeprecatedUsage) String() string {
+	s := fmt.Sprintf("%s: %s.%s is deprecated", u.Position, u.Target.PackageName(), u.Target.Identifier)
+	if u.Deprecated.Notice != "" {
+		s += ": " + u.Deprecated.Notice
+	}
+	return s
+}
+
+// DeprecatedUsages type checks the module, which is located in dir, and returns all usages of its deprecated
+// declarations sorted by position. Like staticcheck, usages within deprecated declarations are ignored, because
+// they usually vanish together.
+func DeprecatedUsages(dir string, m *api.Module) ([]DeprecatedUsage, error) {
+	var paths []string
+	for path := range m.Packages {
+		paths = append(paths, path)
+	}
+
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+		Dir:  dir,
+	}, paths...)
+	if err != nil {
+		return nil, fmt.Errorf("could not load packages from %s: %w", dir, err)
+	}
+
+	targets := deprecatedObjects(m, pkgs)
+	if len(targets) == 0 {
+		return nil, nil
+	}
+
+	var res []DeprecatedUsage
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			for _, d := range file.Decls {
+				if declaresDeprecated(d, pkg.TypesInfo, targets) {
+					continue
+				}
+
+				ast.Inspect(d, func(n ast.Node) bool {
+					ident, ok := n.(*ast.Ident)
+					if !ok {
+						return true
+					}
+
+					target, ok := targets[origin(pkg.TypesInfo.Uses[ident])]
+					if ok {
+						res = append(res, DeprecatedUsage{
+							Target:     target.ref,
+							Deprecated: target.deprecated,
+							Position:   pkg.Fset.Position(ident.Pos()),
+						})
+					}
+					return true
+				})
+			}
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		a, b := res[i].Position, res[j].Position
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	return res, nil
+}
+
+type deprecatedObject struct {
+	ref        api.RefId
+	deprecated *api.Deprecation
+}
+
+// deprecatedObjects maps the type checker objects of all deprecated declarations to their identity.
+func deprecatedObjects(m *api.Module, pkgs []*packages.Package) map[types.Object]deprecatedObject {
+	res := map[types.Object]deprecatedObject{}
+	for _, pkg := range pkgs {
+		p := m.Packages[pkg.PkgPath]
+		if p == nil || pkg.Types == nil {
+			continue
+		}
+
+		scope := pkg.Types.Scope()
+		add := func(obj types.Object, ref api.RefId, d *api.Deprecation) {
+			if obj != nil && d != nil {
+				res[obj] = deprecatedObject{ref: ref, deprecated: d}
+			}
+		}
+
+		for _, block := range p.Consts {
+			for _, c := range block.Content {
+				add(scope.Lookup(c.RefId.Identifier), c.RefId, c.Deprecated)
+			}
+		}
+
+		for name, v := range p.Vars {
+			add(scope.Lookup(name), v.Definition, v.Deprecated)
+		}
+
+		for name, fn := range p.Functions {
+			add(scope.Lookup(name), fn.TypeDefinition, fn.Deprecated)
+		}
+
+		for _, s := range p.Structs {
+			obj := scope.Lookup(s.Name)
+			add(obj, s.TypeDefinition, s.Deprecated)
+			for _, fn := range s.Constructors {
+				add(scope.Lookup(fn.Name), fn.TypeDefinition, fn.Deprecated)
+			}
+
+			if obj == nil {
+				continue
+			}
+
+			for _, method := range s.Methods {
+				if method.Deprecated != nil {
+					m, _, _ := types.LookupFieldOrMethod(obj.Type(), true, pkg.Types, method.Name)
+					add(m, method.TypeDefinition, method.Deprecated)
+				}
+			}
+
+			if st, ok := obj.Type().Underlying().(*types.Struct); ok {
+				for _, f := range s.Fields {
+					if f.Deprecated == nil || f.Name == "" {
+						continue
+					}
+
+					for i := 0; i < st.NumFields(); i++ {
+						if st.Field(i).Name() == f.Name {
+							add(st.Field(i), f.Definition, f.Deprecated)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+// declaresDeprecated returns true, if the declaration declares a deprecated object or a method of a deprecated type.
+func declaresDeprecated(d ast.Decl, info *types.Info, targets map[types.Object]deprecatedObject) bool {
+	deprecated := func(ident *ast.Ident) bool {
+		_, ok := targets[info.Defs[ident]]
+		return ok
+	}
+
+	switch d := d.(type) {
+	case *ast.FuncDecl:
+		if deprecated(d.Name) {
+			return true
+		}
+
+		if d.Recv != nil && len(d.Recv.List) > 0 {
+			if named, ok := derefNamed(info.TypeOf(d.Recv.List[0].Type)); ok {
+				_, ok := targets[named.Obj()]
+				return ok
+			}
+		}
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				if deprecated(spec.Name) {
+					return true
+				}
+			case *ast.ValueSpec:
+				for _, name := range spec.Names {
+					if deprecated(name) {
+						return true
+					}
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func derefNamed(t types.Type) (*types.Named, bool) {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	named, ok := t.(*types.Named)
+	return named, ok
+}
+
+// origin returns the generic declaration of fields and methods of instantiated types.
+func origin(obj types.Object) types.Object {
+	switch obj := obj.(type) {
+	case *types.Var:
+		return obj.Origin()
+	case *types.Func:
+		return obj.Origin()
+	}
+	return obj
+}
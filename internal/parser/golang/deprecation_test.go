@@ -0,0 +1,22 @@
+package golang_test
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/fixture"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+)
+
+func ExampleNewADeprecations() {
+	m, err := fixture.Resolve()
+	if err != nil {
+		panic(err)
+	}
+
+	// the deprecated package store-index and its deprecated function
+	for _, d := range golang.NewADeprecations(m) {
+		fmt.Printf("%q %q %s %q\n", d.Symbol, d.Kind, d.Package.ImportPath, d.Notice)
+	}
+	// Output:
+	// "" "" example.com/m/store-index "use example.com/m/store/index instead."
+	// "<<gd659c1adf91caa7d294d742fa04dcfe27900671d42c396bd39c6a5fa5, Index>>" "func" example.com/m/store-index "use the Field of the Index in store/index instead."
+}
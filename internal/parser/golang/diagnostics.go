@@ -0,0 +1,293 @@
+package golang
+
+import (
+	"github.com/worldiety/gdoc/internal/api"
+	"go/types"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type DiagnosticKind string
+
+const (
+	DiagnosticLink   DiagnosticKind = "unresolved-link" // a [Name] in a comment, which handleComment cannot resolve
+	DiagnosticAnchor DiagnosticKind = "missing-anchor"  // a cross reference to a declaration, which is not documented
+	DiagnosticType   DiagnosticKind = "unknown-type"    // a type, which is neither declared nor imported
+)
+
+// Diagnostic is a problem of the generated documentation, which is located at the declaration causing it.
+type Diagnostic struct {
+	Kind     DiagnosticKind
+	Position api.Position
+	Package  api.ImportPath
+	Symbol   string // the name of the declaration like Type.Method, empty for the package
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	pos := d.Position.String()
+	if pos == "" {
+		pos = d.Package
+	}
+	return pos + ": " + d.Message
+}
+
+var (
+	// unresolvedLink matches the square brackets around a name, which remain after handleComment.
+	unresolvedLink = regexp.MustCompile(`\[(\*?[A-Za-z_][\w.]*)\]`)
+	// linkDefinition matches the definition of a link like [text]: https://..., which go doc does not render.
+	linkDefinition = regexp.MustCompile(`^\[[^\]]+\]:`)
+	// listItem matches an indented list item, all other indented lines are code blocks.
+	listItem = regexp.MustCompile(`^[ \t]+(?:[-*+•]|\d+[.)])[ \t]`)
+	// crossReference matches the asciidoc cross references, which are inserted by handleComment and the type links.
+	crossReference = regexp.MustCompile(`<<([^,<>]+),[^<>]*>>`)
+)
+
+// Diagnose checks the resolved module for comment links, which could not be resolved, cross references of comments
+// and reference lists to undocumented declarations and types, which are neither builtin, declared nor imported.
+// The diagnostics are sorted by position.
+func Diagnose(m *api.Module) []Diagnostic {
+	anchors := anchorIDs(m)
+
+	var res []Diagnostic
+	for importPath, p := range m.Packages {
+		report := func(kind DiagnosticKind, pos api.Position, symbol, msg string) {
+			res = append(res, Diagnostic{Kind: kind, Position: pos, Package: importPath, Symbol: symbol, Message: msg})
+		}
+
+		comment := func(pos api.Position, symbol string, comments ...string) {
+			for _, c := range comments {
+				for _, l := range strings.Split(c, "\n") {
+					if (strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")) && !listItem.MatchString(l) {
+						// a line of a code block, which go doc does not link
+						continue
+					}
+					if linkDefinition.MatchString(l) {
+						continue
+					}
+					for _, match := range unresolvedLink.FindAllStringSubmatchIndex(l, -1) {
+						if name := l[match[2]:match[3]]; isLink(l, match[0], match[1]) && !foreignLink(m, name) {
+							report(DiagnosticLink, pos, symbol, "unresolved link ["+name+"] in the comment of "+owner(p, symbol))
+						}
+					}
+				}
+				for _, match := range crossReference.FindAllStringSubmatch(c, -1) {
+					if !anchors[match[1]] {
+						report(DiagnosticAnchor, pos, symbol, "the comment of "+owner(p, symbol)+" links to the missing anchor "+match[1])
+					}
+				}
+			}
+		}
+
+		typeRef := func(pos api.Position, symbol string, td *api.TypeDesc, params api.Generics) {
+			for _, td := range typeDescs(td) {
+				if msg := checkType(m, p, anchors, td, params); msg != "" {
+					report(DiagnosticType, pos, symbol, msg+" in "+owner(p, symbol))
+				}
+			}
+		}
+
+		function := func(fn *api.Function, symbol string, params api.Generics) {
+			comment(fn.Position, symbol, fn.Comment)
+			for _, fields := range []map[string]*api.Field{fn.Parameters, fn.Results} {
+				for _, f := range fields {
+					typeRef(fn.Position, symbol, f.TypeDesc, append(params, typeParams(fn.Signature)...))
+				}
+			}
+		}
+
+		for _, block := range p.Consts {
+			for _, c := range block.Content {
+				comment(c.Position, c.RefId.Identifier, c.Comment)
+			}
+			if len(block.Content) > 0 {
+				comment(block.Content[0].Position, block.Content[0].RefId.Identifier, block.Doc)
+			}
+		}
+		comment(api.Position{}, "", p.Doc)
+
+		for name, v := range p.Vars {
+			comment(v.Position, name, v.Doc, v.Comment)
+			typeRef(v.Position, name, v.TypeDesc, nil)
+		}
+
+		for name, fn := range p.Functions {
+			function(fn, name, nil)
+		}
+
+		for _, s := range p.Structs {
+			comment(s.Position, s.Name, s.Comment)
+			for _, ref := range s.ReferencedBy {
+				if !anchors[m.Anchor(ref.From)] {
+					from := path.Base(ref.From.ImportPath) + dot + ref.From.Identifier
+					report(DiagnosticAnchor, s.Position, s.Name, "the references of "+owner(p, s.Name)+" link to "+from+", which has no anchor")
+				}
+			}
+			for _, fn := range s.Constructors {
+				function(fn, fn.Name, nil)
+			}
+			for _, method := range s.Methods {
+				function(method.Function, s.Name+dot+method.Name, s.Generics)
+			}
+			for _, f := range s.Fields {
+				symbol := s.Name + dot + f.Name
+				comment(f.Position, symbol, f.Doc, f.Comment)
+				typeRef(f.Position, symbol, f.TypeDesc, s.Generics)
+			}
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		if a.Position.File != b.Position.File {
+			return a.Position.File < b.Position.File
+		}
+		if a.Position.Line != b.Position.Line {
+			return a.Position.Line < b.Position.Line
+		}
+		if a.Position.Column != b.Position.Column {
+			return a.Position.Column < b.Position.Column
+		}
+		return a.Message < b.Message
+	})
+
+	return res
+}
+
+// anchorIDs returns the ids of all anchors, which the asciidoc output contains.
+func anchorIDs(m *api.Module) map[string]bool {
+	res := map[string]bool{}
+	for _, p := range m.Packages {
+		res[m.Anchor(p.PackageDefinition.Package())] = true
+	}
+	for _, sym := range m.Symbols() {
+		res[m.Anchor(sym.Ref)] = true
+	}
+	return res
+}
+
+// checkType returns the problem of a type reference or an empty string.
+func checkType(m *api.Module, p *api.Package, anchors map[string]bool, td *api.TypeDesc, params api.Generics) string {
+	ident := td.Identifier()
+	switch td.TypeOrigin {
+	case api.LocalCustom, api.ExternalCustom:
+		if !anchors[m.Anchor(td.TypeDefinition)] {
+			return "the type " + td.SrcTypeDefinition + " is not documented"
+		}
+	case api.ExternalNonCustom:
+		if !imports(p, td.PkgName()) {
+			return "the package of " + td.SrcTypeDefinition + " is not imported"
+		}
+	case api.BuiltIn:
+		if !isIdentifier(ident) || types.Universe.Lookup(ident) != nil {
+			return ""
+		}
+		for _, g := range params {
+			if g.Name == ident {
+				return ""
+			}
+		}
+		if _, ok := p.Types[ident]; ok {
+			return ""
+		}
+		return "the type " + td.SrcTypeDefinition + " is unknown"
+	}
+	return ""
+}
+
+// typeDescs returns the type itself and the key and value types of maps.
+func typeDescs(td *api.TypeDesc) []*api.TypeDesc {
+	if td == nil {
+		return nil
+	}
+	if td.MapType != nil && td.MapType.KeyType != nil && td.MapType.ValueType != nil {
+		return []*api.TypeDesc{td.MapType.KeyType, td.MapType.ValueType}
+	}
+	return []*api.TypeDesc{td}
+}
+
+// typeParams returns the type parameters of a generic function signature like Map[T any](...).
+func typeParams(signature string) api.Generics {
+	name, rest, ok := strings.Cut(signature, "[")
+	if !ok || strings.Contains(name, "(") {
+		return nil
+	}
+
+	params, _, _ := strings.Cut(rest, "]")
+	var res api.Generics
+	for _, param := range strings.Split(params, ",") {
+		if fields := strings.Fields(param); len(fields) > 0 {
+			res = append(res, &api.Field{Name: fields[0]})
+		}
+	}
+	return res
+}
+
+// imports returns true, if the package imports a package with the given name. Major version suffixes like v2
+// or .v3 of gopkg.in are ignored.
+func imports(p *api.Package, name string) bool {
+	for _, imp := range p.Imports {
+		base := path.Base(string(imp))
+		if major := path.Base(path.Dir(string(imp))); strings.HasPrefix(base, "v") && strings.Trim(base[1:], "0123456789") == "" {
+			base = major
+		}
+		base, _, _ = strings.Cut(base, ".v")
+		if base == name || strings.ReplaceAll(base, "-", "") == name || strings.TrimPrefix(base, "go-") == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isLink returns true, if the brackets between start and end of the line are a doc link. Like go doc, brackets
+// after a word or before a parenthesis or colon are not, e.g. an index expression or a markdown link.
+func isLink(line string, start, end int) bool {
+	if start > 0 {
+		if before, _ := utf8.DecodeLastRuneInString(line[:start]); isWord(before) || before == '[' || before == ']' {
+			return false
+		}
+	}
+
+	if end < len(line) {
+		if after, _ := utf8.DecodeRuneInString(line[end:]); isWord(after) || strings.ContainsRune("(:[", after) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// foreignLink returns true, if the link is qualified by a name, which is not a package of the module, e.g. a
+// package of the standard library like [io.Reader]. Go doc links them, but gdoc cannot check them.
+func foreignLink(m *api.Module, name string) bool {
+	qualifier, _, ok := strings.Cut(strings.TrimPrefix(name, "*"), dot)
+	if !ok {
+		return false
+	}
+
+	return packageByName(m, qualifier) == nil
+}
+
+func isWord(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func owner(p *api.Package, symbol string) string {
+	if symbol == "" {
+		return "package " + p.Name
+	}
+	return p.Name + dot + symbol
+}
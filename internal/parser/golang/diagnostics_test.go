@@ -0,0 +1,31 @@
+package golang_test
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/fixture"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+)
+
+func ExampleDiagnose() {
+	m, err := fixture.Resolve()
+	if err != nil {
+		panic(err)
+	}
+
+	// the variable is referenced by Mode, but not documented anymore
+	delete(m.Packages[fixture.Name+"/store"].Vars, "DefaultMode")
+
+	// links to other modules, link definitions and index expressions are no unresolved links
+	m.Packages[fixture.Name+"/store"].Structs["Store"].Comment = "Store writes to an [io.Writer] within a " +
+		"[context.Context], see [the spec] and [store.Missing].\n\n[the spec]: https://example.com\n[Spec]: https://example.com\n\n" +
+		"It keeps each [Entity] like m[Key]."
+
+	for _, d := range golang.Diagnose(m) {
+		fmt.Printf("%s %s: %s\n", d.Kind, d.Symbol, d.Message)
+	}
+	// Output:
+	// missing-anchor Mode: the references of store.Mode link to store.DefaultMode, which has no anchor
+	// unresolved-link Mode: unresolved link [Tenant] in the comment of store.Mode
+	// unresolved-link Store: unresolved link [Entity] in the comment of store.Store
+	// unresolved-link Store: unresolved link [store.Missing] in the comment of store.Store
+}
@@ -0,0 +1,177 @@
+package golang
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"strings"
+)
+
+// Funcs returns the helpers of the asciidoc templates. They format fragments like signatures, type links and doc
+// comments, while the templates define the layout around them. The helpers link declarations and packages by the
+// given anchors, see AModule Anchor.
+func Funcs(anchor AnchorFunc) map[string]any {
+	return map[string]any{
+		"link": func(ref any, context ...api.RefId) (string, error) {
+			return link(anchor, ref, context...)
+		},
+		"anchor": func(ref any) (string, error) {
+			return anchorID(anchor, ref)
+		},
+		"codeBlock": codeBlock,
+		"typeString": func(td any) (string, error) {
+			return typeString(anchor, td)
+		},
+		"comment":        comment,
+		"plainComment":   formattedComment,
+		"signature":      formattedSignature,
+		"receiver":       receiver,
+		"declaration":    declaration,
+		"value":          value,
+		"since":          since,
+		"deprecated":     deprecated,
+		"source":         source,
+		"sourceListing":  sourceListing,
+		"struck":         struck,
+		"keyword":        keywordFormat,
+		"name":           nameFormat,
+		"variable":       variableFormat,
+		"heading":        heading,
+		"trimLinebreaks": trimAllSuffixLinebreaks,
+		"singleLine":     singleLine,
+		"cell":           cell,
+		"inc":            inc,
+	}
+}
+
+// refID returns the identity of a reference of the model.
+func refID(ref any) (api.RefId, error) {
+	switch ref := ref.(type) {
+	case api.RefId:
+		return ref, nil
+	case ARefId:
+		return ref.RefId, nil
+	default:
+		return api.RefId{}, fmt.Errorf("not a reference: %T", ref)
+	}
+}
+
+// link returns the cross reference to a declaration or a package. Declarations of other packages than the one of
+// the optional context are qualified by their package name.
+func link(anchor AnchorFunc, ref any, context ...api.RefId) (string, error) {
+	switch ref := ref.(type) {
+	case APackageRefID:
+		return NewAPackageRefID(ref.RefId, anchor).String(), nil
+	case APackage:
+		return NewAPackageRefID(ref.PackageDefinition, anchor).String(), nil
+	}
+
+	id, err := refID(ref)
+	if err != nil {
+		return "", err
+	}
+
+	if len(context) > 0 {
+		return NewARefId(id, anchor).LinkFrom(context[0]), nil
+	}
+
+	return NewARefId(id, anchor).Link(), nil
+}
+
+// anchorID returns the anchor of a declaration or a package, see AModule Anchor.
+func anchorID(anchor AnchorFunc, ref any) (string, error) {
+	if p, ok := ref.(APackage); ok {
+		return enclosingDoubleBrackets(square, anchor.of(p.PackageDefinition.Package())), nil
+	}
+
+	id, err := refID(ref)
+	if err != nil {
+		return "", err
+	}
+
+	return NewARefId(id, anchor).AnchorID(), nil
+}
+
+// typeString returns the type of a field, variable or parameter including the links to declared types.
+func typeString(anchor AnchorFunc, td any) (string, error) {
+	switch td := td.(type) {
+	case AField:
+		return trimAllSuffixLinebreaks(td.asciidocFormattedType()), nil
+	case AVariable:
+		return trimAllSuffixLinebreaks(td.asciidocFormattedType()), nil
+	case *api.TypeDesc:
+		return NewATypeDesc(*td, anchor).typeString(), nil
+	case api.TypeDesc:
+		return NewATypeDesc(td, anchor).typeString(), nil
+	case ATypeDesc:
+		return td.typeString(), nil
+	default:
+		return "", fmt.Errorf("not a type: %T", td)
+	}
+}
+
+// heading returns a section title of the given level, which starts a new line.
+func heading(name string, level int) string {
+	return title(name, "", "", level)
+}
+
+// comment converts a doc comment into asciidoc, i.e. lists, indented code and captions.
+func comment(s string) string {
+	return NewAComment(s).String()
+}
+
+// formattedSignature returns the highlighted declaration of a function or method.
+func formattedSignature(fn any) (string, error) {
+	switch fn := fn.(type) {
+	case AFunction:
+		return fn.asciidocFormattedSignature(), nil
+	case AMethod:
+		return fn.asciidocFormattedSignature(), nil
+	default:
+		return "", fmt.Errorf("not a function: %T", fn)
+	}
+}
+
+// receiver returns the highlighted receiver of a method in round brackets.
+func receiver(m AMethod) string {
+	return m.recv().String()
+}
+
+// declaration returns the highlighted type declaration of a struct or interface, which lists the fields or the
+// method set.
+func declaration(s AStruct) string {
+	var fields string
+	for _, f := range s.AFields() {
+		fields += f.String()
+	}
+
+	if s.Interface() {
+		fields += s.asciidocFormattedInterfaceMethods()
+	}
+
+	if fields == "" {
+		fields = fmt.Sprintf("%s%s%s", enclosingBrackets(square, info), enclose(hash, indent(filteredFieldsNotice, 2)), preservedLinebreak)
+	}
+
+	return fmt.Sprintf("%s%s%s%s", s.asciidocFormattedSigOpen(), fields, s.asciidocFormattedSigClose(), preservedLinebreak)
+}
+
+// value returns the value of a constant as written in the source.
+func value(c AConst) string {
+	v, _ := getStringValue(c.Value)
+	return v
+}
+
+// singleLine joins the lines of a text and collapses its white space.
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), ws)
+}
+
+// cell escapes the text of a table cell.
+func cell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// inc returns i+1, e.g. to number the rows of a table.
+func inc(i int) int {
+	return i + 1
+}
@@ -0,0 +1,92 @@
+package golang
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	glossaryFileName = "glossary.yaml"
+	termDirective    = "//gdoc:term "
+)
+
+// packageTerms returns the terms of the //gdoc:term directives of the package in the order of the file names. A
+// directive looks like
+//
+//	//gdoc:term Wiring: the order in which constructors are called
+//
+// and without a colon, the first word is the term.
+func packageTerms(p Package) []api.Term {
+	var res []api.Term
+	for _, fname := range sortedKeys(p.pkg.Files) {
+		for _, group := range p.pkg.Files[fname].Comments {
+			for _, c := range group.List {
+				if name, definition, ok := parseTermDirective(c.Text); ok {
+					res = append(res, api.Term{Name: name, Definition: definition})
+				}
+			}
+		}
+	}
+	return res
+}
+
+// NewGlossary collects the terms of the packages of the module, see api.Package Terms, and of the optional
+// glossary.yaml in the module root dir, which maps each term to its definition. Definitions in the glossary file
+// win over directives. If directives define the same term, the first one in the order of the import paths wins.
+func NewGlossary(dir string, m *api.Module) ([]api.Term, error) {
+	terms := map[string]string{}
+	for _, path := range sortedKeys(m.Packages) {
+		for _, term := range m.Packages[path].Terms {
+			if _, defined := terms[term.Name]; !defined {
+				terms[term.Name] = term.Definition
+			}
+		}
+	}
+
+	buf, err := os.ReadFile(filepath.Join(dir, glossaryFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("cannot read %s: %w", glossaryFileName, err)
+	}
+
+	if len(buf) > 0 {
+		var file map[string]string
+		if err := yaml.Unmarshal(buf, &file); err != nil {
+			return nil, fmt.Errorf("cannot parse %s: %w", glossaryFileName, err)
+		}
+
+		for name, definition := range file {
+			terms[name] = strings.TrimSpace(definition)
+		}
+	}
+
+	var res []api.Term
+	for name, definition := range terms {
+		res = append(res, api.Term{Name: name, Definition: definition})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return strings.ToLower(res[i].Name) < strings.ToLower(res[j].Name)
+	})
+
+	return res, nil
+}
+
+func parseTermDirective(comment string) (name, definition string, ok bool) {
+	text, ok := strings.CutPrefix(comment, termDirective)
+	if !ok {
+		return "", "", false
+	}
+
+	name, definition, found := strings.Cut(text, ":")
+	if !found {
+		name, definition, _ = strings.Cut(strings.TrimSpace(text), ws)
+	}
+
+	name = strings.TrimSpace(name)
+	return name, strings.TrimSpace(definition), name != ""
+}
@@ -0,0 +1,22 @@
+package golang_test
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/fixture"
+)
+
+func Example_glossary() {
+	m, err := fixture.Parse()
+	if err != nil {
+		panic(err)
+	}
+
+	for _, term := range m.Glossary {
+		fmt.Printf("%s: %s\n", term.Name, term.Definition)
+	}
+	// Output:
+	// Entity: a record with an identity, which wins over the directive
+	// Repository: a collection of entities, which can be looked up by their id.
+	// Tenant: the owner of entities
+	// Wiring: the order in which constructors are called
+}
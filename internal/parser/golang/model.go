@@ -5,22 +5,19 @@ import (
 	"github.com/worldiety/gdoc/internal/api"
 	"golang.org/x/exp/slices"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 const (
-	readmeTitle          = "Readme"
-	moduleTitlePrefix    = "Module"
-	packageTitlePrefix   = "Package"
-	structsTitlePrefix   = "Structs"
-	structTitlePrefix    = "Struct"
-	funcsTitlePrefix     = "Functions"
-	variablesTitlePrefix = "Variables"
-	constantsTitlePrefix = "Consts"
 	funcTitlePrefix      = "func"
-	varPrefix            = "var"
 	toc                  = ":toc:"
 	docInfo              = ":docinfo: shared"
 	filteredFieldsNotice = "// contains filtered or unexported fields"
+	sinceNotice          = "Since"
+	deprecatedNotice     = "Deprecated"
+	sourceNotice         = "source"
+	bodyTitle            = "Source"
 )
 
 type ImportPath = string
@@ -34,44 +31,218 @@ func NewAsciiDocHeader() AsciiDocHeader {
 	return AsciiDocHeader{Attributes: s}
 }
 
+// AnchorFunc returns the anchor of a declaration or package, usually api.Module Anchor. The decorators pass it on to
+// the decorators they create and use the anchors of api.HashAnchors without one.
+type AnchorFunc func(id api.RefId) string
+
+func (a AnchorFunc) of(id api.RefId) string {
+	if a == nil {
+		return id.LegacyID()
+	}
+	return a(id)
+}
+
 type AModule struct {
 	Readme   string
 	Name     string
 	Packages map[ImportPath]APackage
+	Diagrams []ADiagram
+	Wiring   AWiringOrder
+	Appendix AAppendix
+	anchor   AnchorFunc
+}
+
+// Anchor returns the anchor of a declaration or package in the scheme of the module.
+func (m AModule) Anchor(id api.RefId) string {
+	return m.anchor.of(id)
+}
+
+// Header returns the document attributes.
+func (m AModule) Header() AsciiDocHeader {
+	return NewAsciiDocHeader()
+}
+
+// PackageList returns the packages sorted by name.
+func (m AModule) PackageList() []APackage {
+	return SortMapValues(m.Packages, func(a, b APackage) bool {
+		return a.Name < b.Name
+	})
+}
+
+// AAppendix is rendered after all packages and contains the symbol index, the deprecated APIs and the glossary.
+type AAppendix struct {
+	Index        AIndex
+	Deprecations ADeprecations
+	Glossary     AGlossary
+}
+
+// AIndexEntry is a linked symbol of the alphabetical index, see also api.Symbol.
+type AIndexEntry struct {
+	Ref     ARefId
+	Kind    api.SymbolKind
+	Package APackageRefID
+}
+
+// AIndex lists all symbols of the module, grouped by their first letter.
+type AIndex []AIndexEntry
+
+// AIndexGroup contains the entries of the index, which start with the same letter.
+type AIndexGroup struct {
+	Letter  string
+	Entries []AIndexEntry
+}
+
+// Groups returns the entries grouped by their upper case first letter.
+func (idx AIndex) Groups() []AIndexGroup {
+	var res []AIndexGroup
+	for _, e := range idx {
+		r, _ := utf8.DecodeRuneInString(e.Ref.Identifier)
+		letter := string(unicode.ToUpper(r))
+		if len(res) == 0 || res[len(res)-1].Letter != letter {
+			res = append(res, AIndexGroup{Letter: letter})
+		}
+		res[len(res)-1].Entries = append(res[len(res)-1].Entries, e)
+	}
+	return res
+}
+
+func NewAIndex(module *api.Module) AIndex {
+	var res AIndex
+	for _, sym := range module.Symbols() {
+		p := module.Packages[sym.Package]
+		if p == nil {
+			continue
+		}
+
+		res = append(res, AIndexEntry{
+			Ref:     NewARefId(sym.Ref, module.Anchor),
+			Kind:    sym.Kind,
+			Package: NewAPackageRefID(p.PackageDefinition, module.Anchor),
+		})
+	}
+	return res
+}
+
+// ADeprecation is a row of the deprecated APIs table. Symbol is the link to the deprecated declaration, which is
+// empty for deprecated packages.
+type ADeprecation struct {
+	Symbol  string
+	Kind    api.SymbolKind
+	Package APackageRefID
+	api.Deprecation
+}
+
+// ADeprecations lists the deprecated packages and symbols of the module.
+type ADeprecations []ADeprecation
+
+func NewADeprecations(module *api.Module) ADeprecations {
+	var res ADeprecations
+	for _, p := range SortMapValues(module.Packages, func(a, b *api.Package) bool {
+		return a.PackageDefinition.ImportPath < b.PackageDefinition.ImportPath
+	}) {
+		if p.Deprecated != nil {
+			res = append(res, ADeprecation{Package: NewAPackageRefID(p.PackageDefinition, module.Anchor), Deprecation: *p.Deprecated})
+		}
+	}
+
+	for _, sym := range module.Symbols() {
+		p := module.Packages[sym.Package]
+		if p == nil || sym.Deprecated == nil {
+			continue
+		}
+
+		res = append(res, ADeprecation{
+			Symbol:      NewARefId(sym.Ref, module.Anchor).String(),
+			Kind:        sym.Kind,
+			Package:     NewAPackageRefID(p.PackageDefinition, module.Anchor),
+			Deprecation: *sym.Deprecated,
+		})
+	}
+	return res
+}
+
+type AGlossary []api.Term
+
+// AWiring is a row of the wiring order table, see also AWiringOrder.
+type AWiring struct {
+	Type        ARefId
+	Constructor ARefId
+	Requires    []ARefId
+	Cyclic      bool
+}
+
+// AWiringOrder lists the constructors of the module in the order, in which they can be called.
+type AWiringOrder []AWiring
+
+// ADiagram is a diagram source block, which is rendered by asciidoctor-diagram.
+type ADiagram struct {
+	Title  string
+	Level  int    // the section level of the title
+	Name   string // the target file name, without extension
+	Lang   string // the block style, e.g. graphviz
+	Source string
+	// Svg is set, if the Source is an already rendered svg, which is inlined into html and
+	// otherwise referenced as image by its file name.
+	Svg bool
 }
 
 func NewAModule(module api.Module) AModule {
 	return AModule{
 		Readme:   module.Readme,
 		Name:     module.Name,
-		Packages: NewAPackages(module.Packages),
+		Packages: NewAPackages(module.Packages, module.Anchor),
+		Appendix: AAppendix{
+			Index:        NewAIndex(&module),
+			Deprecations: NewADeprecations(&module),
+			Glossary:     module.Glossary,
+		},
+		anchor: module.Anchor,
 	}
 }
 
 type APackageRefID struct {
 	api.RefId
+	anchor AnchorFunc
 }
 
-func NewAPackageRefID(id api.RefId) APackageRefID {
-	return APackageRefID{RefId: id}
+func NewAPackageRefID(id api.RefId, anchor AnchorFunc) APackageRefID {
+	return APackageRefID{RefId: id, anchor: anchor}
 }
 func (p APackage) RefID() APackageRefID {
-	return NewAPackageRefID(p.PackageDefinition)
+	return NewAPackageRefID(p.PackageDefinition, p.anchor)
 }
 
 // APackage is a decorator struct for the api.Package struct
 type APackage struct {
 	api.Package
+	Diagrams []ADiagram
+	anchor   AnchorFunc
+}
+
+func (p APackage) AConsts() AConstBlockList {
+	return NewAConstBlockList(p.Consts)
 }
 
-func NewAPackage(packageVal api.Package) APackage {
-	return APackage{Package: packageVal}
+func (p APackage) AVariables() AVariables {
+	return NewAVariables(p.Vars, p.anchor)
 }
 
-func NewAPackages(packagesVal map[ImportPath]*api.Package) map[string]APackage {
+func (p APackage) AStructs() AStructs {
+	return NewAStructs(p.Structs, p.anchor)
+}
+
+func (p APackage) AFunctions() AFunctions {
+	return NewAFunctions(p.Functions, p.anchor)
+}
+
+func NewAPackage(packageVal api.Package, anchor AnchorFunc) APackage {
+	return APackage{Package: packageVal, anchor: anchor}
+}
+
+func NewAPackages(packagesVal map[ImportPath]*api.Package, anchor AnchorFunc) map[string]APackage {
 	pkgs := map[string]APackage{}
 	for importPath, p := range packagesVal {
-		pkgs[importPath] = NewAPackage(*p)
+		pkgs[importPath] = NewAPackage(*p, anchor)
 	}
 	return pkgs
 }
@@ -100,99 +271,96 @@ func NewAComment(s string) AComment {
 	}
 }
 
-type AFunctionComment string
-
 type ARefId struct {
 	api.RefId
+	anchor AnchorFunc
 }
 
-func NewARefId(refId api.RefId) ARefId {
-	return ARefId{RefId: refId}
+func NewARefId(refId api.RefId, anchor AnchorFunc) ARefId {
+	return ARefId{RefId: refId, anchor: anchor}
 }
 
 func (r ARefId) AnchorID() string {
-	return enclosingDoubleBrackets(square, r.ID())
+	return enclosingDoubleBrackets(square, r.anchor.of(r.RefId))
 }
 
-type AStruct struct {
-	api.Struct
+// Link returns the cross reference to the element or just its identifier,
+// if it is not declared in the module and has no import path.
+func (r ARefId) Link() string {
+	if r.ImportPath == "" {
+		return r.Identifier
+	}
+	return r.String()
 }
 
-func (s AStruct) title() string {
-	return bold(keywordFormat(structTitlePrefix), ws, nameFormat(s.Name))
+// LinkFrom returns the Link, whose text is qualified by the package name, if the declaration belongs to another
+// package than from.
+func (r ARefId) LinkFrom(from api.RefId) string {
+	if r.ImportPath == "" || r.ImportPath == from.ImportPath {
+		return r.Link()
+	}
+	return enclosingDoubleBrackets(angle, fmt.Sprintf("%s,%s%s", r.anchor.of(r.RefId), ws, r.PackageName()+dot+r.Identifier))
+}
+
+type AStruct struct {
+	api.Struct
+	anchor AnchorFunc
 }
 
-func NewAStruct(structVal api.Struct) AStruct {
-	return AStruct{Struct: structVal}
+func NewAStruct(structVal api.Struct, anchor AnchorFunc) AStruct {
+	return AStruct{Struct: structVal, anchor: anchor}
 }
 
-func (s AStruct) methods() AMethods {
-	return NewAMethods(s.Methods)
+// AMethods returns the methods sorted by name.
+func (s AStruct) AMethods() []AMethod {
+	return NewAMethods(s.Methods, s.anchor).sort()
 }
 
 type AConstructors map[string]AFunction
 
-func (s AStruct) constructors() AConstructors {
-	return NewAConstructors(s.Constructors)
+func (s AStruct) AConstructors() AConstructors {
+	return NewAConstructors(s.Constructors, s.anchor)
 }
 
 type AStructs map[string]AStruct
 
-func (as AStructs) title() string {
-	return title(structsTitlePrefix, "", "", 3)
-}
-
-func NewAStructs(domainStructs map[string]*api.Struct) AStructs {
+func NewAStructs(domainStructs map[string]*api.Struct, anchor AnchorFunc) AStructs {
 	aStructs := map[string]AStruct{}
 	for _, s := range domainStructs {
-		aStructs[s.Name] = NewAStruct(*s)
+		aStructs[s.Name] = NewAStruct(*s, anchor)
 	}
 	return aStructs
 }
 
-func (s AStruct) comment() AComment {
-	return NewAComment(s.Comment)
-}
-
 func (s AStruct) generics() AGenerics {
-	return NewAGenerics(s.Generics)
+	return NewAGenerics(s.Generics, s.anchor)
 }
 
 type AGenerics []AField
 
-func NewAGenerics(generics api.Generics) AGenerics {
+func NewAGenerics(generics api.Generics, anchor AnchorFunc) AGenerics {
 	var result []AField
 	for _, field := range generics {
-		result = append(result, NewAField(*field))
+		result = append(result, NewAField(*field, anchor))
 	}
 	return result
 }
 
 type AFunction struct {
 	api.Function
+	anchor AnchorFunc
 }
 
-func (af AFunctions) title() string {
-	return title(funcsTitlePrefix, "", "", 3)
-}
-func NewAFunction(functionVal api.Function) AFunction {
-	return AFunction{Function: functionVal}
-}
-
-func (fn AFunction) comment() AFunctionComment {
-	return AFunctionComment(fn.Comment)
+func NewAFunction(functionVal api.Function, anchor AnchorFunc) AFunction {
+	return AFunction{Function: functionVal, anchor: anchor}
 }
 
 func (fn AFunction) RefID() ARefId {
-	return NewARefId(fn.TypeDefinition)
-}
-
-func (fn AFunction) name() string {
-	return name(fn, nil)
+	return NewARefId(fn.TypeDefinition, fn.anchor)
 }
 
 func (m AMethod) function() AFunction {
-	return NewAFunction(*m.Function)
+	return NewAFunction(*m.Function, m.anchor)
 }
 
 func (m AMethod) recv() *ARecv {
@@ -204,43 +372,48 @@ func (m AMethod) name() string {
 }
 
 func (r ARecv) String() string {
+	if r.Name == "" {
+		// the receiver of an interface method
+		return enclosingBrackets(round, typeFormat(r.TypeString))
+	}
 	return enclosingBrackets(round, fmt.Sprintf("%s%s%s", variableFormat(r.Name), ws, typeFormat(r.TypeString)))
 }
 
 func name(fn AFunction, recv *ARecv) string {
 	if recv == nil {
 		return fmt.Sprintf("%s%s%s%s%s", enclosingBrackets(square, keyword),
-			enclose(hash, funcTitlePrefix), ws, fn.RefID().AnchorID(), nameFormat(fn.RefID().Identifier))
+			enclose(hash, funcTitlePrefix), ws, fn.RefID().AnchorID(), struck(nameFormat(fn.RefID().Identifier), fn.Deprecated))
 	} else {
-		return fmt.Sprintf("%s%s%s%s%s%s", enclosingBrackets(square, keyword),
-			enclose(hash, funcTitlePrefix), ws, recv.String(), ws, nameFormat(fn.Name))
+		return fmt.Sprintf("%s%s%s%s%s%s%s", enclosingBrackets(square, keyword),
+			enclose(hash, funcTitlePrefix), ws, recv.String(), ws, fn.RefID().AnchorID(), struck(nameFormat(fn.Name), fn.Deprecated))
 	}
 }
 
 type AFunctions map[string]AFunction
 
-func NewAFunctions(funcs map[string]*api.Function) AFunctions {
+func NewAFunctions(funcs map[string]*api.Function, anchor AnchorFunc) AFunctions {
 	aFunctions := map[string]AFunction{}
 	for _, fn := range funcs {
-		aFunctions[fn.Name] = NewAFunction(*fn)
+		aFunctions[fn.Name] = NewAFunction(*fn, anchor)
 	}
 	return aFunctions
 }
 
-func NewAConstructors(funcs []*api.Function) AConstructors {
+func NewAConstructors(funcs []*api.Function, anchor AnchorFunc) AConstructors {
 	aFunctions := map[string]AFunction{}
 	for _, fn := range funcs {
-		aFunctions[fn.Name] = NewAFunction(*fn)
+		aFunctions[fn.Name] = NewAFunction(*fn, anchor)
 	}
 	return aFunctions
 }
 
 type AMethod struct {
 	api.Method
+	anchor AnchorFunc
 }
 
-func NewAMethod(methodVal api.Method) AMethod {
-	return AMethod{methodVal}
+func NewAMethod(methodVal api.Method, anchor AnchorFunc) AMethod {
+	return AMethod{Method: methodVal, anchor: anchor}
 }
 
 type ARecv struct {
@@ -253,40 +426,33 @@ func NewARecv(s *api.Recv) ARecv {
 
 type AMethods map[string]AMethod
 
-func NewAMethods(methods []*api.Method) AMethods {
+func NewAMethods(methods []*api.Method, anchor AnchorFunc) AMethods {
 	aMethods := map[string]AMethod{}
 	for _, m := range methods {
-		aMethods[m.Name] = NewAMethod(*m)
+		aMethods[m.Name] = NewAMethod(*m, anchor)
 	}
 	return aMethods
 }
 
 type AVariable struct {
 	api.Variable
+	anchor AnchorFunc
 }
 
 func (v AVariable) AnchorID() string {
-	return enclosingDoubleBrackets(square, v.TypeDesc.TypeDefinition.ID())
-}
-
-func (v AVariable) name() AFieldName {
-	return AFieldName(v.Name)
+	return enclosingDoubleBrackets(square, v.anchor.of(v.Definition))
 }
 
-func NewAVariable(v api.Variable) AVariable {
-	return AVariable{Variable: v}
-}
-func (v AVariable) StringRaw() string {
-	return fmt.Sprintf("%s%s%s%s%s",
-		typeFormat(varPrefix), ws, variableFormat(v.Name), ws, v.asciidocFormattedType())
+func NewAVariable(v api.Variable, anchor AnchorFunc) AVariable {
+	return AVariable{Variable: v, anchor: anchor}
 }
 
 type AVariables map[string]AVariable
 
-func NewAVariables(vars map[string]*api.Variable) AVariables {
+func NewAVariables(vars map[string]*api.Variable, anchor AnchorFunc) AVariables {
 	nv := map[string]AVariable{}
 	for name, v := range vars {
-		nv[name] = NewAVariable(*v)
+		nv[name] = NewAVariable(*v, anchor)
 	}
 	return nv
 }
@@ -297,6 +463,28 @@ func (v AVariables) sort() []AVariable {
 	})
 }
 
+// Plain returns the variables without doc comment sorted by name, which are declared together.
+func (v AVariables) Plain() []AVariable {
+	var res []AVariable
+	for _, current := range v.sort() {
+		if current.Doc == "" {
+			res = append(res, current)
+		}
+	}
+	return res
+}
+
+// Documented returns the variables with doc comment sorted by name, which are declared separately.
+func (v AVariables) Documented() []AVariable {
+	var res []AVariable
+	for _, current := range v.sort() {
+		if current.Doc != "" {
+			res = append(res, current)
+		}
+	}
+	return res
+}
+
 func (consts AConstBlock) sort() AConstBlock {
 	slices.SortFunc(consts.Content, func(a, b api.Constant) bool {
 		return a.RefId.Identifier < b.RefId.Identifier
@@ -304,10 +492,13 @@ func (consts AConstBlock) sort() AConstBlock {
 	return consts
 }
 
-func (consts AConstBlock) consts() []AConst {
+// AConsts returns the constants of the block with a known value sorted by name.
+func (consts AConstBlock) AConsts() []AConst {
 	res := make([]AConst, 0)
-	for _, constant := range consts.Content {
-		res = append(res, NewAConst(constant))
+	for _, constant := range consts.sort().Content {
+		if _, ok := getStringValue(constant.Value); ok {
+			res = append(res, NewAConst(constant))
+		}
 	}
 	return res
 }
@@ -316,10 +507,6 @@ type AConst struct {
 	api.Constant
 }
 
-func (c AConst) name() AFieldName {
-	return AFieldName(c.RefId.Identifier)
-}
-
 func NewAConst(c api.Constant) AConst {
 	return AConst{c}
 }
@@ -334,6 +521,17 @@ func NewAConstBlock(consts api.ConstantBlock) AConstBlock {
 
 type AConstBlockList []AConstBlock
 
+// Blocks returns the blocks, which contain at least one constant with a known value.
+func (blocks AConstBlockList) Blocks() []AConstBlock {
+	var res []AConstBlock
+	for _, block := range blocks {
+		if len(block.AConsts()) > 0 {
+			res = append(res, block)
+		}
+	}
+	return res
+}
+
 func NewAConstBlockList(blocks []api.ConstantBlock) AConstBlockList {
 	res := make([]AConstBlock, 0)
 	for _, block := range blocks {
@@ -348,41 +546,27 @@ func (ms AMethods) sort() []AMethod {
 	})
 }
 
-type commentStatus int
-
-const (
-	uncommented commentStatus = iota
-	commented
-)
-
-func (AVariables) title() string {
-	return title(variablesTitlePrefix, "", "", 3)
-}
-
-func (AConstBlockList) title() string {
-	return title(constantsTitlePrefix, "", "", 3)
-}
-
 type AField struct {
 	api.Field
+	anchor AnchorFunc
 }
 
 type AFields map[string]AField
 
-func NewAField(fieldVal api.Field) AField {
-	return AField{Field: fieldVal}
+func NewAField(fieldVal api.Field, anchor AnchorFunc) AField {
+	return AField{Field: fieldVal, anchor: anchor}
 }
 
 func (s AStruct) AFields() []AField {
 	aFields := make([]AField, 0)
 	for _, f := range s.Fields {
-		aFields = append(aFields, NewAField(*f))
+		aFields = append(aFields, NewAField(*f, s.anchor))
 	}
 	return aFields
 }
 
 func (f AField) typeDescription() ATypeDesc {
-	return NewATypeDesc(*f.TypeDesc)
+	return NewATypeDesc(*f.TypeDesc, f.anchor)
 }
 
 func (f AField) comment() AComment {
@@ -405,14 +589,15 @@ type AMapType struct {
 
 type ATypeDesc struct {
 	api.TypeDesc
+	anchor AnchorFunc
 }
 
-func NewATypeDesc(typeDescVal api.TypeDesc) ATypeDesc {
-	return ATypeDesc{TypeDesc: typeDescVal}
+func NewATypeDesc(typeDescVal api.TypeDesc, anchor AnchorFunc) ATypeDesc {
+	return ATypeDesc{TypeDesc: typeDescVal, anchor: anchor}
 }
 
 func (td ATypeDesc) RefId() ARefId {
-	return NewARefId(td.TypeDefinition)
+	return NewARefId(td.TypeDefinition, td.anchor)
 }
 
 func (td ATypeDesc) Prefix() string {
@@ -444,16 +629,16 @@ func (td ATypeDesc) lastClosedArrayBracketIndex() int {
 
 func (td ATypeDesc) localCustomTypeLink() string {
 	return fmt.Sprintf("%s%s", td.Prefix(), enclosingDoubleBrackets(angle, fmt.Sprintf("%s,%s%s%s",
-		td.TypeDefinition.ID(), ws, enclosingBrackets(square, typ3), enclose(hash, td.Identifier()))))
+		td.anchor.of(td.TypeDefinition), ws, enclosingBrackets(square, typ3), enclose(hash, td.Identifier()))))
 }
 
 func (td ATypeDesc) externalCustomTypeLink() string {
 	// custom type from external package from this project
 	return fmt.Sprintf("%s%s%s%s",
 		// remove the asterisk to find the linked id, it's still displayed in the doc
-		td.Prefix(), enclosingDoubleBrackets(angle, fmt.Sprintf("%s,%s%s%s", td.PkgName(), ws,
+		td.Prefix(), enclosingDoubleBrackets(angle, fmt.Sprintf("%s,%s%s%s", td.anchor.of(td.TypeDefinition.Package()), ws,
 			enclosingBrackets(square, typ3), enclose(hash, td.PkgName()))), dot,
-		enclosingDoubleBrackets(angle, fmt.Sprintf("%s,%s%s%s", td.TypeDefinition.ID(), ws,
+		enclosingDoubleBrackets(angle, fmt.Sprintf("%s,%s%s%s", td.anchor.of(td.TypeDefinition), ws,
 			enclosingBrackets(square, typ3), enclose(hash, td.Identifier()))))
 }
 
@@ -9,142 +9,8 @@ import (
 	"strings"
 )
 
-func (h AsciiDocHeader) String() string {
-	var s string
-	for _, a := range h.Attributes {
-		s += fmt.Sprintf("%s%s", a, simpleLinebreak)
-	}
-	s = strings.Trim(s, simpleLinebreak)
-	return s
-}
-
-func (m AModule) String() string {
-	return fmt.Sprintf("%s%s%s", m.title(), simpleLinebreak, m.readme())
-}
-
 func (id APackageRefID) String() string {
-	return enclosingDoubleBrackets(angle, fmt.Sprintf("%s,%s%s", id.Identifier, ws, id.Identifier))
-}
-
-func (p APackage) String() string {
-	return fmt.Sprintf("%s%s", p.title(), p.readme())
-}
-
-func (fn AFunction) String() string {
-	return fmt.Sprintf("%s%s%s%s%s", bold(fn.name()), preservedLinebreak,
-		codeBlock(fn.asciidocFormattedSignature()), simpleLinebreak, fn.comment().String())
-}
-
-func (af AFunctions) String() string {
-	return af.title()
-}
-
-func (c AConstructors) String() string {
-	var constructorString string
-	for _, fn := range c {
-		constructorString += fn.String()
-	}
-	return constructorString
-}
-
-func (m AMethod) String() string {
-
-	return fmt.Sprintf("%s%s%s%s%s", bold(m.name()), preservedLinebreak,
-		codeBlock(m.asciidocFormattedSignature()), simpleLinebreak, NewAFunction(*m.Function).comment().String())
-}
-
-func (ms AMethods) String() string {
-	var methodString string
-	for _, m := range ms.sort() {
-		methodString += m.String()
-	}
-	return methodString
-}
-
-func (v AVariable) String() string {
-	var docString string
-	if v.Doc != "" {
-		docString = NewADoc(v.Doc).String() + preservedLinebreak
-	}
-	return codeBlock(fmt.Sprintf("%s%s%s%s%s%s%s%s%s%s%s",
-		docString, builtinFormat(varPrefix), ws, v.AnchorID(), v.name().String(), ws, trimAllSuffixLinebreaks(v.asciidocFormattedType()), ws, passThrough(commentPrefix), ws, v.Comment))
-}
-
-func (v AVariables) String() string {
-	var s string
-	varMap := make(map[commentStatus]string)
-
-	for _, current := range v.sort() {
-		if current.Doc == "" {
-			if current.Comment != "" {
-				varMap[uncommented] += trimAllSuffixLinebreaks(current.StringRaw()) + ws + commentPrefix + ws + current.Comment + simpleLinebreak
-			} else {
-				varMap[uncommented] += current.StringRaw()
-			}
-		} else {
-			varMap[commented] += current.String()
-		}
-	}
-
-	var noCommentVars, commentedVars string
-	if varMap[uncommented] != "" {
-		noCommentVars = codeBlock(varMap[uncommented])
-	}
-	if varMap[commented] != "" {
-		commentedVars = varMap[commented]
-	}
-	s = fmt.Sprintf("%s%s%s", noCommentVars, simpleLinebreak, commentedVars)
-	s = strings.Trim(s, simpleLinebreak)
-	return fmt.Sprintf("%s%s%s", v.title(), simpleLinebreak, s)
-}
-
-func (c AConst) String() string {
-	var s, comm string
-
-	if value, ok := getStringValue(c.Value); ok {
-		if c.Comment != "" {
-			comm = fmt.Sprintf("%s%s%s", commentPrefix, ws, c.Comment)
-		}
-		s = fmt.Sprintf("%s%s%s%s%s%s%s", typeFormat(c0nst), ws, c.name().String(), ws, operatorFormat(equals), ws, value)
-		if comm != "" {
-			s += comm
-		}
-	}
-	return s
-}
-
-func (consts AConstBlock) String() string {
-	var s string
-	for _, c := range consts.sort().consts() {
-		if constString := c.String(); constString != "" {
-			s += c.String() + preservedLinebreak
-		}
-	}
-	s = strings.TrimSuffix(s, preservedLinebreak)
-	if consts.Doc != "" && s != "" {
-		s = codeBlock(s) + consts.Doc
-	} else if s != "" {
-		s = codeBlock(s)
-	}
-
-	return s
-}
-
-func (blocks AConstBlockList) String() string {
-	var s string
-	for _, block := range blocks {
-		if blockString := block.String(); blockString != "" {
-			s += blockString + preservedLinebreak
-		}
-
-	}
-	s = strings.TrimSuffix(s, preservedLinebreak)
-
-	if s != "" {
-		s = blocks.title() + preservedLinebreak + s
-	}
-
-	return s
+	return enclosingDoubleBrackets(angle, fmt.Sprintf("%s,%s%s", id.anchor.of(id.Package()), ws, id.Identifier))
 }
 
 func (f AField) String() string {
@@ -164,15 +30,20 @@ func (f AField) String() string {
 
 	var nameString string
 	if slices.Contains(f.Stereotypes, api.StereotypeProperty) {
-		nameString = indent(f.name().String(), 2)
+		var anchor string
+		if f.Definition.Named() {
+			anchor = NewARefId(f.Definition, f.anchor).AnchorID()
+		}
+		nameString = indent(anchor+struck(f.name().String(), f.Deprecated), 2)
 	} else if f.Name != "" {
-		nameString = f.name().String()
+		nameString = struck(f.name().String(), f.Deprecated)
 	}
-	s := fmt.Sprintf("%s%s%s%s%s",
+	s := fmt.Sprintf("%s%s%s%s%s%s",
 		doc,
 		nameString,
 		whiteSpace,
 		trimAllSuffixLinebreaks(f.asciidocFormattedType()),
+		since(f.Since)+deprecated(f.Deprecated),
 		comment,
 	)
 
@@ -191,60 +62,27 @@ func (m AMapType) String() string {
 	return fmt.Sprintf("AMapType{MapType: %v}", m.MapType)
 }
 func (r ARefId) String() string {
-	return enclosingDoubleBrackets(angle, fmt.Sprintf("%s,%s%s", r.ID(), ws, r.Identifier))
-}
-
-func (as AStructs) String() string {
-	return as.title()
-}
-
-func (s AStruct) String() string {
-	var commentString string
-	if s.Comment != "" {
-		commentString = s.comment().String()
-	}
-	var fieldsString string
-	for _, f := range s.AFields() {
-		fieldsString += f.String()
-	}
-
-	if fieldsString == "" {
-		fieldsString = fmt.Sprintf("%s%s%s", enclosingBrackets(square, info),
-			enclose(hash, indent(filteredFieldsNotice, 2)), preservedLinebreak)
-	}
-
-	var constructorString string
-	if s.constructors() != nil {
-		constructorString = s.constructors().String()
-	}
-
-	return fmt.Sprintf("%s%s%s%s%s%s%s%s%s%s", s.title(), preservedLinebreak, codeBlock(fmt.Sprintf("%s%s%s%s", s.asciidocFormattedSigOpen(),
-		fieldsString, s.asciidocFormattedSigClose(), preservedLinebreak)), commentString, constructorString, s.methods().String(), simpleLinebreak, simpleLinebreak, "'''", simpleLinebreak)
-}
-
-func (afc AFunctionComment) String() string {
-	if afc != "" {
-		return formattedComment(string(afc))
-	}
-	return ""
+	return enclosingDoubleBrackets(angle, fmt.Sprintf("%s,%s%s", r.anchor.of(r.RefId), ws, r.Identifier))
 }
 
 func (generics AGenerics) String() string {
 	var s string
 	var sep = comma + ws
-	typeMap := make(map[ATypeDesc][]string, 0)
+	var anchor AnchorFunc
+	typeMap := make(map[api.TypeDesc][]string, 0)
 	for _, g := range generics {
-		if typeMap[g.typeDescription()] == nil {
-			typeMap[g.typeDescription()] = make([]string, 0)
+		anchor = g.anchor
+		if typeMap[*g.TypeDesc] == nil {
+			typeMap[*g.TypeDesc] = make([]string, 0)
 		}
-		typeMap[g.typeDescription()] = append(typeMap[g.typeDescription()], g.Name)
+		typeMap[*g.TypeDesc] = append(typeMap[*g.TypeDesc], g.Name)
 	}
 	for ts, nameList := range typeMap {
 		for _, name := range nameList {
 			s += nameFormat(name) + sep
 		}
 		s = strings.TrimSuffix(s, sep)
-		s += ws + ts.typeString() + sep
+		s += ws + NewATypeDesc(ts, anchor).typeString() + sep
 	}
 	s = strings.TrimSuffix(s, sep)
 	if s != "" {
@@ -0,0 +1,22 @@
+package golang_test
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+)
+
+func ExampleAIndex_Groups() {
+	var idx golang.AIndex
+	for _, name := range []string{"Ölfeld", "über", "Übung", "Zeit"} {
+		idx = append(idx, golang.AIndexEntry{Ref: golang.NewARefId(api.NewRefID("example.com/m", name), nil)})
+	}
+
+	for _, g := range idx.Groups() {
+		fmt.Println(g.Letter, len(g.Entries))
+	}
+	// Output:
+	// Ö 1
+	// Ü 2
+	// Z 1
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/worldiety/gdoc/internal/api"
 	"go/ast"
 	"go/doc"
+	"go/types"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -27,10 +28,20 @@ func newModule(dir string, modname string, pkgs map[string]Package) (*api.Module
 		for _, p := range pkgs {
 			np := newPackage(p)
 			np.Readme = tryLoadReadme(p.dir)
+			np.Terms = packageTerms(p)
 			m.Packages[p.dpkg.ImportPath] = np
 		}
 	}
 
+	addDeprecations(m)
+	addPositions(dir, m, pkgs)
+
+	glossary, err := NewGlossary(dir, m)
+	if err != nil {
+		return nil, err
+	}
+	m.Glossary = glossary
+
 	return m, nil
 }
 
@@ -77,20 +88,42 @@ func newPackage(pkg Package) *api.Package {
 		}
 	}
 
-	if len(pkg.dpkg.Consts) > 0 {
-		for _, value := range pkg.dpkg.Consts {
+	if pkg.xtest != nil {
+		// the examples of the external test package document the tested package
+		for _, f := range doc.New(pkg.xtest, pkg.dpkg.ImportPath, doc.AllDecls|doc.PreserveAST).Funcs {
+			if !strings.HasPrefix(f.Name, "Example") {
+				continue
+			}
+			if p.Functions == nil {
+				p.Functions = map[string]*api.Function{}
+			}
+			if _, ok := p.Functions[f.Name]; !ok {
+				p.Functions[f.Name] = newFunc(f)
+			}
+		}
+	}
+
+	// go/doc associates typed constants and variables like A Mode = iota with their type
+	consts, vars := pkg.dpkg.Consts, pkg.dpkg.Vars
+	for _, t := range pkg.dpkg.Types {
+		consts = append(consts, t.Consts...)
+		vars = append(vars, t.Vars...)
+	}
+
+	if len(consts) > 0 {
+		for _, value := range consts {
 			tmp := make([]api.Constant, 0)
 			constants, docV := newValue(value)
 			for _, d := range constants {
-				tmp = append(tmp, api.NewConstant(api.NewRefID(pkg.dpkg.ImportPath, d.name), d.comment, d.value))
+				tmp = append(tmp, api.NewConstant(api.NewRefID(pkg.dpkg.ImportPath, d.name), d.comment, d.value, d.expr))
 			}
 			p.Consts = append(p.Consts, api.NewConstantBlock(tmp, docV))
 		}
 	}
 
-	if len(pkg.dpkg.Vars) > 0 {
+	if len(vars) > 0 {
 		p.Vars = map[string]*api.Variable{}
-		for _, value := range pkg.dpkg.Vars {
+		for _, value := range vars {
 			for _, spec := range value.Decl.Specs {
 				switch t := spec.(type) {
 				case *ast.ValueSpec:
@@ -155,8 +188,15 @@ func newStruct(value *doc.Type) *api.Struct {
 					}
 				}
 			}
-			if structType, ok := s.Type.(*ast.StructType); ok {
-				for _, field := range structType.Fields.List {
+			switch t := s.Type.(type) {
+			case *ast.StructType:
+				myStruct.Stereotypes = append(myStruct.Stereotypes, api.StereotypeStruct)
+				for _, field := range t.Fields.List {
+					if len(field.Names) == 0 {
+						if embedded := newEmbeddedField(field, myStruct); embedded != nil {
+							f = append(f, embedded)
+						}
+					}
 					for _, ident := range field.Names {
 						if isExported(ident.Name) {
 							field := newField(field, myStruct, ident.Name)
@@ -165,6 +205,29 @@ func newStruct(value *doc.Type) *api.Struct {
 						}
 					}
 				}
+			case *ast.InterfaceType:
+				myStruct.Stereotypes = append(myStruct.Stereotypes, api.StereotypeInterface)
+				for _, field := range t.Methods.List {
+					if len(field.Names) == 0 {
+						if embedded := newEmbeddedField(field, myStruct); embedded != nil {
+							f = append(f, embedded)
+						}
+						continue
+					}
+
+					ft, ok := field.Type.(*ast.FuncType)
+					if !ok {
+						continue
+					}
+
+					for _, ident := range field.Names {
+						if isExported(ident.Name) {
+							myStruct.Methods = append(myStruct.Methods, newInterfaceMethod(ident.Name, field.Doc.Text(), ft, myStruct))
+						} else {
+							myStruct.Sealed = true
+						}
+					}
+				}
 			}
 		}
 	}
@@ -192,6 +255,29 @@ func newStruct(value *doc.Type) *api.Struct {
 	return myStruct
 }
 
+// newEmbeddedField returns the embedded field, if it denotes an exported type and not a type constraint union.
+func newEmbeddedField(f *ast.Field, s *api.Struct) *api.Field {
+	switch t := f.Type.(type) {
+	case *ast.StarExpr:
+		if !isTypeName(t.X) {
+			return nil
+		}
+	default:
+		if !isTypeName(t) {
+			return nil
+		}
+	}
+
+	embedded := newField(f, s, "")
+	if !isExported(embedded.TypeDesc.Identifier()) {
+		return nil
+	}
+
+	embedded.TypeDesc.Linebreak = true
+	embedded.Stereotypes = append(embedded.Stereotypes, api.StereotypeEmbedded)
+	return embedded
+}
+
 func newMethod(docFunc *doc.Func, recv *api.Field) *api.Method {
 
 	return &api.Method{
@@ -200,13 +286,35 @@ func newMethod(docFunc *doc.Func, recv *api.Field) *api.Method {
 	}
 }
 
+// newInterfaceMethod creates a method declared by an interface. Its receiver is the interface itself and has no name.
+func newInterfaceMethod(name, comment string, ft *ast.FuncType, s *api.Struct) *api.Method {
+	recv := api.NewField("", "", "", api.NewTypeDesc(api.RefId{}, s.Name, false, nil), s)
+	return &api.Method{
+		Function: newFuncType(name, comment, ft),
+		Recv:     api.NewRecv(recv, "", s.Name),
+	}
+}
+
 func newFunc(docFunc *doc.Func) *api.Function {
+	return newFuncType(docFunc.Name, docFunc.Doc, docFunc.Decl.Type)
+}
+
+func newFuncType(name, comment string, fn *ast.FuncType) *api.Function {
 	f := &api.Function{
-		Name:    docFunc.Name,
-		Comment: docFunc.Doc,
+		Name:      name,
+		Comment:   comment,
+		Signature: signature(name, fn),
 	}
 
-	fn := docFunc.Decl.Type
+	if fn.TypeParams != nil {
+		for _, p := range fn.TypeParams.List {
+			for _, n := range p.Names {
+				nf := newField(p, nil, n.Name)
+				nf.Stereotypes = []api.Stereotype{api.StereotypeGeneric}
+				f.Generics = append(f.Generics, nf)
+			}
+		}
+	}
 
 	inArgs := fn.Params.List
 	if len(inArgs) > 0 {
@@ -260,8 +368,8 @@ func newField(f *ast.Field, s *api.Struct, name string) *api.Field {
 
 	m := &api.MapType{}
 	if ok, mapType := isMapField(f); ok {
-		m.KeyType = api.NewTypeDesc(api.RefId{}, ast2str(mapType.Key), isPointerType(mapType.Key), m)
-		m.ValueType = api.NewTypeDesc(api.RefId{}, ast2str(mapType.Value), isPointerType(mapType.Value), m)
+		m.KeyType = api.NewTypeDesc(api.RefId{}, ast2str(mapType.Key), isPointerType(mapType.Key), nil)
+		m.ValueType = api.NewTypeDesc(api.RefId{}, ast2str(mapType.Value), isPointerType(mapType.Value), nil)
 	}
 
 	n := api.NewField(name, f.Comment.Text(), f.Doc.Text(), api.NewTypeDesc(api.RefId{}, ast2str(f.Type), isPointerType(f.Type), m), s)
@@ -274,6 +382,17 @@ func newField(f *ast.Field, s *api.Struct, name string) *api.Field {
 	return n
 }
 
+// isTypeName checks if the given ast.Expr is a (qualified) type name
+func isTypeName(expr ast.Expr) bool {
+	switch t := expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		return true
+	case *ast.IndexExpr:
+		return isTypeName(t.X)
+	}
+	return false
+}
+
 // isMapField checks if the given *ast.Field is a map or not
 func isMapField(f *ast.Field) (bool, *ast.MapType) {
 	if f == nil {
@@ -313,6 +432,7 @@ type docValue struct {
 	comment string
 	name    string
 	value   any
+	expr    string
 }
 
 func newValue(value *doc.Value) ([]docValue, string) {
@@ -327,11 +447,18 @@ func newValue(value *doc.Value) ([]docValue, string) {
 				if !name.IsExported() {
 					continue
 				}
-				res = append(res, docValue{
+				v := docValue{
 					comment: t.Comment.Text(),
 					name:    name.Name,
-					value:   t.Values[i],
-				})
+				}
+
+				// consts may repeat the previous expression implicitly
+				if i < len(t.Values) {
+					v.value = t.Values[i]
+					v.expr = types.ExprString(t.Values[i])
+				}
+
+				res = append(res, v)
 			}
 
 		}
@@ -340,8 +467,32 @@ func newValue(value *doc.Value) ([]docValue, string) {
 	return res, strings.TrimSpace(groupDoc + "\n" + actualDoc)
 }
 
+// signature returns the name followed by type parameters, parameters and results, as declared in an interface.
+func signature(name string, fn *ast.FuncType) string {
+	var tparams []string
+	if fn.TypeParams != nil {
+		for _, p := range fn.TypeParams.List {
+			var names []string
+			for _, n := range p.Names {
+				names = append(names, n.Name)
+			}
+			tparams = append(tparams, strings.Join(names, comma+ws)+ws+types.ExprString(p.Type))
+		}
+	}
+
+	s := name
+	if len(tparams) > 0 {
+		s += enclosingBrackets(square, strings.Join(tparams, comma+ws))
+	}
+
+	return s + strings.TrimPrefix(types.ExprString(fn), funcTitle)
+}
+
 func ast2str(n ast.Node) string {
 	switch t := n.(type) {
+	case nil:
+		// e.g. the type of a variable, which is inferred from its value
+		return ""
 	case *ast.Ident:
 		return t.Name
 	case *ast.SelectorExpr:
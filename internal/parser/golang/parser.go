@@ -15,10 +15,12 @@ import (
 )
 
 type Package struct {
-	pkg  *ast.Package
-	dpkg *doc.Package
-	ppkg *packages.Package
-	dir  string
+	pkg   *ast.Package
+	xtest *ast.Package // the external test package like name_test, which may contain examples
+	dpkg  *doc.Package
+	ppkg  *packages.Package
+	dir   string
+	fset  *token.FileSet
 }
 
 // Parse the specified directory, which will be the current one, if none was specified by the user.
@@ -48,6 +50,13 @@ func Parse(dir string, onlyImports ...string) (*api.Module, error) {
 			panic(fmt.Errorf("cannot happen: %w", err))
 		}
 
+		importPath := modName + "/" + rel
+		if len(onlyImports) > 0 {
+			if !slices.Contains(onlyImports, importPath) {
+				continue
+			}
+		}
+
 		pkgs, err := parser.ParseDir(fset, dir, func(info fs.FileInfo) bool {
 			return strings.HasSuffix(info.Name(), ".go")
 		}, parser.ParseComments)
@@ -56,21 +65,27 @@ func Parse(dir string, onlyImports ...string) (*api.Module, error) {
 			return nil, fmt.Errorf("cannot parse: %w", err)
 		}
 
-		importPath := modName + "/" + rel
-		if len(onlyImports) > 0 {
-			if !slices.Contains(onlyImports, importPath) {
+		var xtest *ast.Package
+		for _, astPkg := range pkgs {
+			// the external test package shares the directory and import path of the tested package
+			if strings.HasSuffix(astPkg.Name, "_test") {
+				xtest = astPkg
 				continue
 			}
-		}
 
-		for _, astPkg := range pkgs {
-			pkg := doc.New(astPkg, importPath, doc.AllDecls)
+			pkg := doc.New(astPkg, importPath, doc.AllDecls|doc.PreserveAST)
 			module[pkg.ImportPath] = Package{
 				pkg:  astPkg,
 				dpkg: pkg,
 				dir:  dir,
+				fset: fset,
 			}
 		}
+
+		if pkg, ok := module[importPath]; ok && xtest != nil {
+			pkg.xtest = xtest
+			module[importPath] = pkg
+		}
 	}
 
 	return newModule(modRoot, modName, module)
@@ -0,0 +1,164 @@
+package golang
+
+import (
+	"github.com/worldiety/gdoc/internal/api"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// addPositions sets the source position of each declaration of the module. The declarations are looked up by
+// their name, methods and fields like Type.Method, in the syntax trees of their package.
+func addPositions(modRoot string, m *api.Module, pkgs map[string]Package) {
+	for path, pkg := range pkgs {
+		p := m.Packages[path]
+		if p == nil || pkg.fset == nil {
+			continue
+		}
+
+		positions := declPositions(modRoot, pkg)
+		p.Position = positions[""]
+
+		for _, block := range p.Consts {
+			for i := range block.Content {
+				block.Content[i].Position = positions[block.Content[i].RefId.Identifier]
+			}
+		}
+
+		for name, v := range p.Vars {
+			v.Position = positions[name]
+		}
+
+		for name, fn := range p.Functions {
+			fn.Position = positions[name]
+		}
+
+		for _, s := range p.Structs {
+			s.Position = positions[s.Name]
+			for _, fn := range s.Constructors {
+				fn.Position = positions[fn.Name]
+			}
+			for _, method := range s.Methods {
+				method.Position = positions[s.Name+dot+method.Name]
+			}
+			for _, f := range s.Fields {
+				name := f.Name
+				if name == "" {
+					name = f.TypeDesc.Identifier()
+				}
+				f.Position = positions[s.Name+dot+name]
+			}
+		}
+	}
+}
+
+// declPositions returns the positions of all declared identifiers of the package.
+func declPositions(modRoot string, pkg Package) map[string]api.Position {
+	res := map[string]api.Position{}
+	add := func(name string, pos, end token.Pos) {
+		p := pkg.fset.Position(pos)
+		file := p.Filename
+		if rel, err := filepath.Rel(modRoot, file); err == nil {
+			file = filepath.ToSlash(rel)
+		}
+		res[name] = api.Position{File: file, Line: p.Line, Column: p.Column, EndLine: pkg.fset.Position(end).Line}
+	}
+
+	// a declaration, which is repeated in files for different build tags, is located in the first file by name
+	first := func(name string, pos, end token.Pos) {
+		if _, ok := res[name]; !ok {
+			add(name, pos, end)
+		}
+	}
+
+	// the package is located at the file with the package doc or at the first file
+	names := sortedKeys(pkg.pkg.Files)
+	for i, name := range names {
+		if file := pkg.pkg.Files[name]; i == 0 || file.Doc != nil {
+			add("", file.Package, file.Name.End())
+			if file.Doc != nil {
+				break
+			}
+		}
+	}
+
+	for _, name := range names {
+		for _, decl := range pkg.pkg.Files[name].Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				name := d.Name.Name
+				if d.Recv != nil && len(d.Recv.List) > 0 {
+					name = recvTypeName(d.Recv.List[0].Type) + dot + name
+				}
+				first(name, d.Name.Pos(), d.End())
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						first(s.Name.Name, s.Name.Pos(), s.End())
+						addMemberPositions(s, first)
+					case *ast.ValueSpec:
+						for _, ident := range s.Names {
+							first(ident.Name, ident.Pos(), s.End())
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if pkg.xtest != nil {
+		for _, name := range sortedKeys(pkg.xtest.Files) {
+			for _, decl := range pkg.xtest.Files[name].Decls {
+				if d, ok := decl.(*ast.FuncDecl); ok && d.Recv == nil && strings.HasPrefix(d.Name.Name, "Example") {
+					first(d.Name.Name, d.Name.Pos(), d.End())
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+// addMemberPositions adds the fields of a struct and the methods of an interface. Embedded members are named
+// by their type.
+func addMemberPositions(s *ast.TypeSpec, add func(name string, pos, end token.Pos)) {
+	var fields *ast.FieldList
+	switch t := s.Type.(type) {
+	case *ast.StructType:
+		fields = t.Fields
+	case *ast.InterfaceType:
+		fields = t.Methods
+	}
+
+	if fields == nil {
+		return
+	}
+
+	for _, f := range fields.List {
+		if len(f.Names) == 0 {
+			add(s.Name.Name+dot+recvTypeName(f.Type), f.Type.Pos(), f.End())
+		}
+		for _, ident := range f.Names {
+			add(s.Name.Name+dot+ident.Name, ident.Pos(), f.End())
+		}
+	}
+}
+
+// recvTypeName returns the name of a receiver or embedded type without pointer, package and type parameters.
+func recvTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return recvTypeName(t.X)
+	case *ast.IndexExpr:
+		return recvTypeName(t.X)
+	case *ast.IndexListExpr:
+		return recvTypeName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
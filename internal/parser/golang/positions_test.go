@@ -0,0 +1,38 @@
+package golang_test
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/fixture"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+	"os"
+	"path/filepath"
+)
+
+func Example_positions() {
+	dir, err := os.MkdirTemp("", "positions")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := fixture.Copy(dir); err != nil {
+		panic(err)
+	}
+
+	// the function is declared once per operating system
+	for _, goos := range []string{"windows", "linux", "darwin"} {
+		src := "//go:build " + goos + "\n\npackage store\n\n// Platform returns the operating system.\nfunc Platform() string {\n\treturn \"" + goos + "\"\n}\n"
+		if err := os.WriteFile(filepath.Join(dir, "store", "platform_"+goos+".go"), []byte(src), 0644); err != nil {
+			panic(err)
+		}
+	}
+
+	m, err := golang.Parse(dir)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(m.Packages[fixture.Name+"/store"].Functions["Platform"].Position)
+	// Output:
+	// store/platform_darwin.go:6:6
+}
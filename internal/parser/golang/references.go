@@ -0,0 +1,105 @@
+package golang
+
+import (
+	"github.com/worldiety/gdoc/internal/api"
+	"sort"
+)
+
+// addReferences inverts the resolved type definitions of all fields, parameters, results, type parameters and
+// variables into the ReferencedBy list of each struct declared in the module.
+func addReferences(m *api.Module) {
+	add := func(td *api.TypeDesc, ref api.Reference) {
+		for _, td := range referencedTypes(td) {
+			if td.TypeOrigin != api.LocalCustom && td.TypeOrigin != api.ExternalCustom {
+				continue
+			}
+
+			p := m.Packages[td.TypeDefinition.ImportPath]
+			if p == nil {
+				continue
+			}
+
+			if s := p.Structs[td.TypeDefinition.Identifier]; s != nil && !containsReference(s.ReferencedBy, ref) {
+				s.ReferencedBy = append(s.ReferencedBy, ref)
+			}
+		}
+	}
+
+	addFunction := func(from api.RefId, fn *api.Function) {
+		for _, f := range fn.Parameters {
+			add(f.TypeDesc, api.Reference{From: from, Kind: api.StereotypeParameter, Name: f.Name})
+		}
+		for _, f := range fn.Results {
+			add(f.TypeDesc, api.Reference{From: from, Kind: api.StereotypeParameterResult, Name: f.Name})
+		}
+		for _, g := range fn.Generics {
+			add(g.TypeDesc, api.Reference{From: from, Kind: api.StereotypeGeneric, Name: g.Name})
+		}
+	}
+
+	for path, p := range m.Packages {
+		for _, fn := range p.Functions {
+			addFunction(fn.TypeDefinition, fn)
+		}
+
+		for name, v := range p.Vars {
+			add(v.TypeDesc, api.Reference{From: api.NewRefID(path, name), Kind: api.StereotypeVariable, Name: name})
+		}
+
+		for _, s := range p.Structs {
+			for _, f := range s.Fields {
+				kind := api.Stereotype(api.StereotypeProperty)
+				if f.Name == "" {
+					kind = api.StereotypeEmbedded
+				}
+				add(f.TypeDesc, api.Reference{From: s.TypeDefinition, Kind: kind, Name: f.Name})
+			}
+			for _, g := range s.Generics {
+				add(g.TypeDesc, api.Reference{From: s.TypeDefinition, Kind: api.StereotypeGeneric, Name: g.Name})
+			}
+			for _, fn := range s.Constructors {
+				addFunction(fn.TypeDefinition, fn)
+			}
+			for _, method := range s.Methods {
+				addFunction(method.TypeDefinition, method.Function)
+			}
+		}
+	}
+
+	for _, p := range m.Packages {
+		for _, s := range p.Structs {
+			sort.Slice(s.ReferencedBy, func(i, j int) bool {
+				a, b := s.ReferencedBy[i], s.ReferencedBy[j]
+				if a.From != b.From {
+					return a.From.ImportPath+dot+a.From.Identifier < b.From.ImportPath+dot+b.From.Identifier
+				}
+				if a.Kind != b.Kind {
+					return a.Kind < b.Kind
+				}
+				return a.Name < b.Name
+			})
+		}
+	}
+}
+
+// referencedTypes returns the type description itself or the key and value types of a map.
+func referencedTypes(td *api.TypeDesc) []*api.TypeDesc {
+	if td == nil {
+		return nil
+	}
+
+	if td.MapType != nil && (td.MapType.KeyType != nil || td.MapType.ValueType != nil) {
+		return append(referencedTypes(td.MapType.KeyType), referencedTypes(td.MapType.ValueType)...)
+	}
+
+	return []*api.TypeDesc{td}
+}
+
+func containsReference(refs []api.Reference, ref api.Reference) bool {
+	for _, r := range refs {
+		if r == ref {
+			return true
+		}
+	}
+	return false
+}
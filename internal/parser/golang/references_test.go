@@ -0,0 +1,54 @@
+package golang_test
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/fixture"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+	"os"
+	"path/filepath"
+)
+
+func Example_references() {
+	dir, err := os.MkdirTemp("", "references")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := fixture.Copy(dir); err != nil {
+		panic(err)
+	}
+
+	// the constraint of the type parameter references Mode
+	src := `package store
+
+// Distinct returns the modes without duplicates.
+func Distinct[M Mode](modes ...M) []M {
+	return nil
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "store", "distinct.go"), []byte(src), 0644); err != nil {
+		panic(err)
+	}
+
+	m, err := golang.Parse(dir)
+	if err != nil {
+		panic(err)
+	}
+
+	pkgs, err := golang.NewChecker(dir, fixture.Name).Packages(fixture.Name + "/store")
+	if err != nil {
+		panic(err)
+	}
+	golang.ResolvePackages(m, pkgs)
+
+	for _, ref := range m.Packages[fixture.Name+"/store"].Structs["Mode"].ReferencedBy {
+		fmt.Println(ref.From.Identifier, ref.Kind, ref.Name)
+	}
+	// Output:
+	// DefaultMode variable DefaultMode
+	// Distinct generic M
+	// NewStore parameter mode
+	// Store property Mode
+	// Strict variable Strict
+}
@@ -3,12 +3,16 @@ package golang
 import (
 	"fmt"
 	"github.com/worldiety/gdoc/internal/api"
+	"go/types"
 	"golang.org/x/tools/go/packages"
+	"path"
+	"sort"
 	"strings"
 )
 
 type loadedPackages struct {
 	pkgs      map[string]*packages.Package
+	paths     map[string]*packages.Package // import path => package
 	fnMap     map[string]*api.Function
 	structMap map[string]*api.Struct
 }
@@ -16,61 +20,166 @@ type loadedPackages struct {
 func newLoadedPackages() *loadedPackages {
 	return &loadedPackages{
 		pkgs:  map[string]*packages.Package{},
+		paths: map[string]*packages.Package{},
 		fnMap: map[string]*api.Function{},
 	}
 }
 
-func Resolve(m *api.Module) error {
+// Resolve adds the type information of the packages.Package to the module, which is located in dir. The doc links
+// of the comments are kept, see LinkComments.
+func Resolve(dir string, m *api.Module) error {
 	lp := newLoadedPackages()
-	var err error
 
-	for dir, _ := range m.Packages {
-		err = lp.loadPackages(dir)
-		if err != nil {
-			return err
-		}
+	var dirs []string
+	for dir := range m.Packages {
+		dirs = append(dirs, dir)
 	}
 
-	addTypeInformation(m, lp)
-	addCommentLinks(m)
+	// load all packages at once, so that they share the same type universe
+	if err := lp.loadPackages(dir, dirs...); err != nil {
+		return err
+	}
 
+	resolve(m, lp)
 	return nil
 }
 
-func addCommentLinks(m *api.Module) {
+// ResolvePackages adds the type information of already loaded packages to the module. Only the name, the import
+// path and the types of each package are used.
+func ResolvePackages(m *api.Module, pkgs []*packages.Package) {
+	lp := newLoadedPackages()
+	for _, pkg := range pkgs {
+		lp.pkgs[pkg.Name] = pkg
+		lp.paths[pkg.PkgPath] = pkg
+	}
+
+	resolve(m, lp)
+}
+
+func resolve(m *api.Module, lp *loadedPackages) {
+	addTypeInformation(m, lp)
+	addImplements(m, lp)
+	addReferences(m)
+}
+
+// LinkComments replaces the doc links like [Name] in the comments of the resolved module by cross references to
+// the anchors of the module, see api.Module Anchor. Thus, the anchor scheme must be set before.
+func LinkComments(m *api.Module) {
 	for _, p := range m.Packages {
 		for _, function := range p.Functions {
 			function.Comment = handleComment(function.Comment, p, m)
 		}
 		for _, s := range p.Structs {
 			s.Comment = handleComment(s.Comment, p, m)
+			for _, fn := range s.Constructors {
+				fn.Comment = handleComment(fn.Comment, p, m)
+			}
+			for _, method := range s.Methods {
+				method.Comment = handleComment(method.Comment, p, m)
+			}
+			for _, f := range s.Fields {
+				f.Comment = handleComment(f.Comment, p, m)
+				f.Doc = handleComment(f.Doc, p, m)
+			}
 		}
 		for _, v := range p.Vars {
 			v.Comment = handleComment(v.Comment, p, m)
 			v.Doc = handleComment(v.Doc, p, m)
 		}
 		for _, consts := range p.Consts {
-			for _, c := range consts.Content {
-				c.Comment = handleComment(c.Comment, p, m)
+			for i := range consts.Content {
+				consts.Content[i].Comment = handleComment(consts.Content[i].Comment, p, m)
 			}
 		}
 	}
 }
 
-func (lp *loadedPackages) loadPackages(dir string) error {
+func (lp *loadedPackages) loadPackages(dir string, dirs ...string) error {
+	if len(dirs) == 0 {
+		return nil
+	}
+
 	pkgs, err := packages.Load(
-		&packages.Config{Mode: packages.NeedName | packages.NeedTypes | packages.NeedModule, Tests: false}, dir)
+		&packages.Config{Mode: packages.NeedName | packages.NeedTypes | packages.NeedImports | packages.NeedModule, Dir: dir, Tests: false}, dirs...)
 	if err != nil {
-		return fmt.Errorf("could not load packages from %s: %w", dir, err)
+		return fmt.Errorf("could not load packages from %s: %w", strings.Join(dirs, ", "), err)
 	}
 
 	for _, pkg := range pkgs {
 		lp.pkgs[pkg.Name] = pkg
+		lp.paths[pkg.PkgPath] = pkg
 	}
 
 	return nil
 }
 
+// lookupType returns the named type declared in the package with the given import path or nil.
+func (lp *loadedPackages) lookupType(path, name string) *types.Named {
+	pkg := lp.paths[path]
+	if pkg == nil || pkg.Types == nil {
+		return nil
+	}
+
+	obj, ok := pkg.Types.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+
+	named, _ := obj.Type().(*types.Named)
+	return named
+}
+
+// addImplements links each non-interface type to the interfaces declared in the module, which it realises
+// either by value or by pointer. Generic types and interfaces without methods are ignored.
+func addImplements(m *api.Module, lp *loadedPackages) {
+	type iface struct {
+		ref api.RefId
+		typ *types.Interface
+	}
+
+	var ifaces []iface
+	for path, p := range m.Packages {
+		for _, s := range p.Structs {
+			if !s.Interface() {
+				continue
+			}
+
+			named := lp.lookupType(path, s.Name)
+			if named == nil || named.TypeParams().Len() > 0 {
+				continue
+			}
+
+			if it, ok := named.Underlying().(*types.Interface); ok && it.IsMethodSet() && it.NumMethods() > 0 {
+				ifaces = append(ifaces, iface{ref: s.TypeDefinition, typ: it})
+			}
+		}
+	}
+
+	for path, p := range m.Packages {
+		for _, s := range p.Structs {
+			if s.Interface() {
+				continue
+			}
+
+			named := lp.lookupType(path, s.Name)
+			if named == nil || named.TypeParams().Len() > 0 {
+				continue
+			}
+
+			for _, i := range ifaces {
+				if types.Implements(named, i.typ) || types.Implements(types.NewPointer(named), i.typ) {
+					s.Implements = append(s.Implements, i.ref)
+				}
+			}
+
+			sort.Slice(s.Implements, func(i, j int) bool {
+				a, b := s.Implements[i], s.Implements[j]
+				return a.ImportPath+dot+a.Identifier < b.ImportPath+dot+b.Identifier
+			})
+		}
+	}
+}
+
 // add information to the module and all it's sub-parts that the ast package does not provide, but the packages.Package does
 func addTypeInformation(m *api.Module, lp *loadedPackages) {
 	for path, p := range m.Packages {
@@ -83,18 +192,44 @@ func addTypeInformation(m *api.Module, lp *loadedPackages) {
 }
 func addVariableInfo(p *api.Package, lp *loadedPackages, path string) {
 	for id, v := range p.Vars {
+		v.Definition = api.NewRefID(path, id)
+		if v.TypeDesc.SrcTypeDefinition == "" {
+			inferVariableType(v, lp.paths[path])
+		}
 		v.TypeDesc.TypeDefinition = api.NewRefID(path, id)
 		pkgName := strings.Replace(v.TypeDesc.SrcTypeDefinition, "*", "", -1)
 		if _, ok := lp.pkgs[pkgName]; ok {
 			p.Types[v.Name] = v.TypeDesc.TypeDefinition
 		}
-		typeDescInfo(v.Name, v.TypeDesc, lp)
+		typeDescInfo(path, v.TypeDesc, lp)
 	}
 }
+
+// inferVariableType sets the source type of a variable, which is declared without an explicit type.
+func inferVariableType(v *api.Variable, pkg *packages.Package) {
+	if pkg == nil || pkg.Types == nil {
+		return
+	}
+
+	obj := pkg.Types.Scope().Lookup(v.Name)
+	if obj == nil {
+		return
+	}
+
+	v.TypeDesc.SrcTypeDefinition = types.TypeString(obj.Type(), func(p *types.Package) string {
+		if p == pkg.Types {
+			return ""
+		}
+		return p.Name()
+	})
+	_, v.TypeDesc.Pointer = obj.Type().(*types.Pointer)
+}
+
 func addConstantInfo(p *api.Package, path string) {
 	for _, block := range p.Consts {
-		for _, constant := range block.Content {
-			constant.RefId = api.NewRefID(path, p.PackageDefinition.ImportPath)
+		for i := range block.Content {
+			constant := &block.Content[i]
+			constant.RefId = api.NewRefID(path, constant.RefId.Identifier)
 			p.Types[constant.RefId.Identifier] = constant.RefId
 		}
 	}
@@ -106,6 +241,9 @@ func addFunctionInfo(p *api.Package, lp *loadedPackages, path string) {
 		p.Types[function.Name] = function.TypeDefinition
 		handleFields(function.Parameters, p, lp)
 		handleFields(function.Results, p, lp)
+		for _, g := range function.Generics {
+			handleField(g, p, lp)
+		}
 	}
 }
 
@@ -115,6 +253,9 @@ func addConstructorInfo(s *api.Struct, p *api.Package, lp *loadedPackages) {
 		p.Types[function.Name] = function.TypeDefinition
 		handleFields(function.Parameters, p, lp)
 		handleFields(function.Results, p, lp)
+		for _, g := range function.Generics {
+			handleField(g, p, lp)
+		}
 	}
 }
 func addStructInfo(p *api.Package, lp *loadedPackages, path string) {
@@ -123,12 +264,16 @@ func addStructInfo(p *api.Package, lp *loadedPackages, path string) {
 		p.Types[s.Name] = s.TypeDefinition
 
 		for _, f := range s.Fields {
+			if f.Name != "" {
+				f.Definition = api.NewRefID(path, s.Name+dot+f.Name)
+			}
 			handleField(f, p, lp)
 		}
 		for _, g := range s.Generics {
 			handleField(g, p, lp)
 		}
 		for _, m := range s.Methods {
+			m.TypeDefinition = api.NewRefID(path, s.Name+dot+m.Name)
 			handleMethod(m, p, lp)
 		}
 
@@ -138,7 +283,9 @@ func addStructInfo(p *api.Package, lp *loadedPackages, path string) {
 }
 
 func handleMethod(m *api.Method, p *api.Package, lp *loadedPackages) {
-	typeDescInfo(p.Name, m.Recv.TypeDesc, lp)
+	typeDescInfo(p.PackageDefinition.ImportPath, m.Recv.TypeDesc, lp)
+	handleFields(m.Parameters, p, lp)
+	handleFields(m.Results, p, lp)
 }
 
 func handleComment(comment string, p *api.Package, m *api.Module) string {
@@ -147,29 +294,27 @@ func handleComment(comment string, p *api.Package, m *api.Module) string {
 	}
 	replacementMap := map[string]string{}
 	// split word
-	for _, s := range strings.Split(comment, ws) {
-		// check for type from external package
+	for _, s := range strings.Fields(comment) {
+		// check for type from external package, either as doc link like [store.Mode] or as plain word
 		if externalPkg(s) {
-			parts := strings.Split(s, dot)
-			for path, extPkg := range m.Packages {
-				// check import paths for ext package name
-				if strings.HasSuffix(path, parts[0]) {
-					// add replacement string for pkg name to map
-					pkgReplacement := NewAPackageRefID(extPkg.PackageDefinition).String()
-					var typeReplacement string
-					if t, ok := extPkg.Types[parts[1]]; ok {
-						// add replacement string for external type to map
-						typeReplacement = NewARefId(t).String()
-					}
-					replacementMap[s] = fmt.Sprintf("%s%s%s", pkgReplacement, dot, typeReplacement)
+			name := s
+			if enclosedInSquareBrackets(s) {
+				name = removeEnclosingSquaredBrackets(s)
+			}
+			parts := strings.Split(name, dot)
+			if extPkg := packageByName(m, parts[0]); extPkg != nil {
+				if t, ok := extPkg.Types[parts[1]]; ok {
+					// add replacement string for pkg name and external type to map
+					pkgReplacement := NewAPackageRefID(extPkg.PackageDefinition, m.Anchor).String()
+					replacementMap[s] = fmt.Sprintf("%s%s%s", pkgReplacement, dot, xref(m, t))
 				}
 			}
 		} else if t, ok := p.Types[s]; ok {
 			// if from current package
-			replacementMap[t.Identifier] = NewARefId(t).String()
+			replacementMap[t.Identifier] = xref(m, t)
 		} else if enclosedInSquareBrackets(s) {
 			if t, ok := p.Types[removeEnclosingSquaredBrackets(s)]; ok {
-				replacementMap[s] = NewARefId(t).String()
+				replacementMap[s] = xref(m, t)
 			}
 		}
 	}
@@ -181,11 +326,27 @@ func handleComment(comment string, p *api.Package, m *api.Module) string {
 	return comment
 }
 
+// packageByName returns the package of the module with the given name or last import path element. If several
+// packages match, the one with the lowest import path wins.
+func packageByName(m *api.Module, name string) *api.Package {
+	for _, importPath := range sortedKeys(m.Packages) {
+		if p := m.Packages[importPath]; p.Name == name || path.Base(importPath) == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// xref returns the cross reference to the anchor of the declaration.
+func xref(m *api.Module, id api.RefId) string {
+	return enclosingDoubleBrackets(angle, fmt.Sprintf("%s,%s%s", m.Anchor(id), ws, id.Identifier))
+}
+
 func handleField(f *api.Field, p *api.Package, lp *loadedPackages) {
 	if f.TypeDesc.Map() {
-		handleMapType(f, p.Name, lp)
+		handleMapType(f, p.PackageDefinition.ImportPath, lp)
 	} else {
-		typeDescInfo(p.Name, f.TypeDesc, lp)
+		typeDescInfo(p.PackageDefinition.ImportPath, f.TypeDesc, lp)
 	}
 }
 
@@ -195,7 +356,7 @@ func handleFields(parameters map[string]*api.Field, currentPackage *api.Package,
 	}
 }
 
-func handleMapType(f *api.Field, pName string, lp *loadedPackages) {
+func handleMapType(f *api.Field, path string, lp *loadedPackages) {
 	keyTypeDef, valueTypeDef := f.TypeDesc.MapSrcDefs()
 	f.TypeDesc.MapType = &api.MapType{}
 	f.TypeDesc.MapType.KeyType = &api.TypeDesc{SrcTypeDefinition: keyTypeDef,
@@ -206,17 +367,17 @@ func handleMapType(f *api.Field, pName string, lp *loadedPackages) {
 		Pointer:   strings.Contains(valueTypeDef, "*"),
 		Linebreak: true,
 	}
-	typeDescInfo(pName, f.TypeDesc.MapType.KeyType, lp)
-	typeDescInfo(pName, f.TypeDesc.MapType.ValueType, lp)
+	typeDescInfo(path, f.TypeDesc.MapType.KeyType, lp)
+	typeDescInfo(path, f.TypeDesc.MapType.ValueType, lp)
 }
 
-func typeDescInfo(pName string, td *api.TypeDesc, lp *loadedPackages) {
+func typeDescInfo(path string, td *api.TypeDesc, lp *loadedPackages) {
 	var importPath string
 	var origin api.TypeOrigin
 
 	// from current package or built-in
 	if currentPackageOrBuiltIn(td) {
-		if p := lp.pkgs[pName]; p != nil {
+		if p := lp.paths[path]; p != nil {
 			if t := p.Types.Scope().Lookup(td.Identifier()); t != nil {
 				importPath = p.PkgPath
 				origin = api.LocalCustom
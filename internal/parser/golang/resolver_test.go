@@ -0,0 +1,46 @@
+package golang
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/packages"
+)
+
+func Example_inferVariableType() {
+	m, err := Parse("testdata/infer")
+	if err != nil {
+		panic(err)
+	}
+
+	// type check from source, which does not depend on the export data format of the installed go version
+	fset := token.NewFileSet()
+	astPkgs, err := parser.ParseDir(fset, "testdata/infer/conf", nil, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	var files []*ast.File
+	for _, f := range astPkgs["conf"].Files {
+		files = append(files, f)
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	tpkg, err := conf.Check("example.com/infer/conf", fset, files, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, name := range []string{"Default", "Replacer"} {
+		v := m.Packages["example.com/infer/conf"].Vars[name]
+		fmt.Printf("%s %q", name, v.TypeDesc.SrcTypeDefinition)
+		inferVariableType(v, &packages.Package{Types: tpkg})
+		fmt.Println(" =>", v.TypeDesc.SrcTypeDefinition, v.TypeDesc.Pointer)
+	}
+	// Output:
+	// Default "" => *Config true
+	// Replacer "" => *strings.Replacer true
+}
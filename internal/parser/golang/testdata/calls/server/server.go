@@ -0,0 +1,22 @@
+// Package server declares functions, which call each other directly and through unexported functions.
+package server
+
+// Server answers the requests.
+type Server struct{}
+
+// NewServer creates a Server.
+func NewServer() *Server {
+	return &Server{}
+}
+
+// Serve handles a request.
+func (s *Server) Serve() {}
+
+// Run creates a Server and lets it serve.
+func Run() {
+	serve(NewServer())
+}
+
+func serve(s *Server) {
+	s.Serve()
+}
@@ -0,0 +1,15 @@
+// Package conf declares variables without an explicit type.
+package conf
+
+import "strings"
+
+// Config is set up by the variables below.
+type Config struct {
+	Name string
+}
+
+// Default is the configuration, which is used if none is given.
+var Default = &Config{Name: "default"}
+
+// Replacer escapes the names.
+var Replacer = strings.NewReplacer("/", "-")
@@ -10,14 +10,21 @@ import (
 	"strings"
 )
 
-// PkgDirs returns all directories containing any go file.
+// PkgDirs returns all directories containing any go file, which belong to the module at root.
 func PkgDirs(root string) ([]string, error) {
 	var res []string
 	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
-		if d.IsDir() && strings.HasPrefix(d.Name(), ".") {
+		// like the go tool, ignore hidden directories, testdata and nested modules
+		if d.IsDir() && (strings.HasPrefix(d.Name(), ".") || strings.HasPrefix(d.Name(), "_") || d.Name() == "testdata") {
 			return filepath.SkipDir
 		}
 
+		if d.IsDir() && path != root {
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+		}
+
 		if d.IsDir() {
 			files, err := os.ReadDir(path)
 			if err != nil {
@@ -98,3 +105,17 @@ func SortMapValues[K comparable, V any](m map[K]V, less func(a, b V) bool) []V {
 
 	return tmp
 }
+
+// sortedKeys returns the keys of the map in ascending order, e.g. to iterate deterministically over files.
+func sortedKeys[K ~string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+
+	return keys
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/worldiety/gdoc/internal/api"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// defaultLimit is the number of search results, unless the limit parameter says otherwise.
+const defaultLimit = 20
+
+// packages responds with the summaries of all packages.
+func (s *Server) packages(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.index().Packages())
+}
+
+// symbol responds with the symbol at /api/symbol/{importPath}/{ident}. Members are identified like Type.Method.
+func (s *Server) symbol(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/symbol/")
+	i := strings.LastIndex(path, "/")
+	if i < 0 {
+		writeError(w, http.StatusBadRequest, "expected /api/symbol/{importPath}/{ident}")
+		return
+	}
+
+	idx := s.index()
+	e, ok := idx.Lookup(idx.Anchor(api.NewRefID(path[:i], path[i+1:])))
+	if !ok {
+		writeError(w, http.StatusNotFound, "no symbol "+path[i+1:]+" in "+path[:i])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, e)
+}
+
+// search responds with the symbols matching the q parameter.
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		writeError(w, http.StatusBadRequest, "missing query parameter q")
+		return
+	}
+
+	limit := defaultLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid limit "+v)
+			return
+		}
+		limit = n
+	}
+
+	writeJSON(w, http.StatusOK, s.index().Search(q, limit))
+}
+
+// implementers responds with the types realizing the interface at /api/implementers/{id}.
+func (s *Server) implementers(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/implementers/")
+	idx := s.index()
+	if _, ok := idx.Lookup(id); !ok {
+		writeError(w, http.StatusNotFound, "no symbol "+id)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, idx.Implementers(id))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(buf)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	buf, _ := json.Marshal(struct{ Error string }{msg})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(buf)
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/generator/html"
+	"go/doc"
+	"sort"
+	"strings"
+)
+
+// Entry is an indexed symbol together with its declaration, which is one of *api.Struct, *api.Function,
+// *api.Method, *api.Field, *api.Variable or api.Constant.
+type Entry struct {
+	api.Symbol
+	Declaration any
+}
+
+// PackageSummary describes a package of the module without its declarations.
+type PackageSummary struct {
+	ImportPath api.ImportPath
+	Name       string
+	Synopsis   string
+	Deprecated *api.Deprecation
+	Symbols    int
+}
+
+// Index provides fast lookups of the symbols of a module by their anchor, see api.Module Anchor.
+type Index struct {
+	module       *api.Module
+	packages     []PackageSummary
+	symbols      []api.Symbol
+	entries      map[string]Entry
+	implementers map[string][]api.RefId // the types, which realize an interface
+}
+
+func NewIndex(m *api.Module) *Index {
+	idx := &Index{module: m, entries: map[string]Entry{}, implementers: map[string][]api.RefId{}}
+	idx.symbols = m.Symbols()
+
+	decls := map[string]any{}
+	counts := map[api.ImportPath]int{}
+	for _, p := range m.Packages {
+		for _, block := range p.Consts {
+			for _, c := range block.Content {
+				decls[m.Anchor(c.RefId)] = c
+			}
+		}
+		for _, v := range p.Vars {
+			decls[m.Anchor(v.Definition)] = v
+		}
+		for _, fn := range p.Functions {
+			decls[m.Anchor(fn.TypeDefinition)] = fn
+		}
+		for _, s := range p.Structs {
+			decls[m.Anchor(s.TypeDefinition)] = s
+			for _, fn := range s.Constructors {
+				decls[m.Anchor(fn.TypeDefinition)] = fn
+			}
+			for _, method := range s.Methods {
+				decls[m.Anchor(method.TypeDefinition)] = method
+			}
+			for _, f := range s.Fields {
+				decls[m.Anchor(f.Definition)] = f
+			}
+			for _, ref := range s.Implements {
+				idx.implementers[m.Anchor(ref)] = append(idx.implementers[m.Anchor(ref)], s.TypeDefinition)
+			}
+		}
+	}
+
+	for _, sym := range idx.symbols {
+		idx.entries[m.Anchor(sym.Ref)] = Entry{Symbol: sym, Declaration: decls[m.Anchor(sym.Ref)]}
+		counts[sym.Package]++
+	}
+
+	for path, p := range m.Packages {
+		idx.packages = append(idx.packages, PackageSummary{
+			ImportPath: path,
+			Name:       p.Name,
+			Synopsis:   new(doc.Package).Synopsis(html.Text(p.Doc)),
+			Deprecated: p.Deprecated,
+			Symbols:    counts[path],
+		})
+	}
+	sort.Slice(idx.packages, func(i, j int) bool { return idx.packages[i].ImportPath < idx.packages[j].ImportPath })
+
+	for _, refs := range idx.implementers {
+		sort.Slice(refs, func(i, j int) bool {
+			if refs[i].ImportPath != refs[j].ImportPath {
+				return refs[i].ImportPath < refs[j].ImportPath
+			}
+			return refs[i].Identifier < refs[j].Identifier
+		})
+	}
+
+	return idx
+}
+
+// Packages returns the summaries of all packages sorted by import path.
+func (idx *Index) Packages() []PackageSummary {
+	return idx.packages
+}
+
+// Anchor returns the anchor of the declaration, like it is used by Lookup and Implementers.
+func (idx *Index) Anchor(ref api.RefId) string {
+	return idx.module.Anchor(ref)
+}
+
+// Lookup returns the symbol with the given anchor.
+func (idx *Index) Lookup(id string) (Entry, bool) {
+	e, ok := idx.entries[id]
+	return e, ok
+}
+
+// Implementers returns the types, which realize the interface with the given anchor.
+func (idx *Index) Implementers(id string) []api.Symbol {
+	res := []api.Symbol{}
+	for _, ref := range idx.implementers[id] {
+		if e, ok := idx.entries[idx.Anchor(ref)]; ok {
+			res = append(res, e.Symbol)
+		}
+	}
+	return res
+}
+
+// Search returns at most limit symbols, whose name contains the query ignoring the case. Exact matches come first,
+// followed by prefix matches and then by any other match.
+func (idx *Index) Search(query string, limit int) []api.Symbol {
+	query = strings.ToLower(query)
+	rank := func(sym api.Symbol) int {
+		name := strings.ToLower(sym.Name)
+		switch {
+		case name == query:
+			return 0
+		case strings.HasPrefix(name, query):
+			return 1
+		case strings.Contains(name, query):
+			return 2
+		default:
+			return -1
+		}
+	}
+
+	res := []api.Symbol{}
+	for _, sym := range idx.symbols {
+		if rank(sym) >= 0 {
+			res = append(res, sym)
+		}
+	}
+
+	// the symbols are already sorted by name, which stays the order within each rank
+	sort.SliceStable(res, func(i, j int) bool { return rank(res[i]) < rank(res[j]) })
+	if len(res) > limit {
+		res = res[:limit]
+	}
+
+	return res
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/fixture"
+)
+
+func ExampleIndex_Search() {
+	m, err := fixture.Resolve()
+	if err != nil {
+		panic(err)
+	}
+	m.Anchors = api.NewAnchors(api.ReadableAnchors{Module: m.Name}, m)
+
+	idx := NewIndex(m)
+	for _, sym := range idx.Search("mode", 10) {
+		fmt.Println(sym.Kind, sym.Name, idx.Anchor(sym.Ref))
+	}
+
+	// the anchors of the search results look up the declarations
+	e, ok := idx.Lookup("store--NewStore")
+	fmt.Println(ok, e.Symbol.Name, e.Declaration.(*api.Function).Signature)
+
+	for _, p := range idx.Packages() {
+		fmt.Printf("%s %d %q\n", p.ImportPath, p.Symbols, p.Synopsis)
+	}
+	// Output:
+	// type Mode store--Mode
+	// var DefaultMode store--DefaultMode
+	// field Store.Mode store--Store--Mode
+	// true NewStore NewStore(mode Mode) *Store
+	// example.com/m/store 10 "Package store keeps the entities of the example module."
+	// example.com/m/store-index 1 "Package storeindex is the former home of the index package."
+	// example.com/m/store/index 2 "Package index looks up the entities of a store by other fields than their id."
+}
@@ -0,0 +1,156 @@
+// Package server serves the rendered documentation from memory and notifies browsers about new versions using
+// server-sent events. The json api below /api queries the module, which belongs to the page.
+package server
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/generator/search"
+	"html"
+	"html/template"
+	"net/http"
+	"sync"
+)
+
+// ReloadScript reloads the page, whenever the server publishes a new version. It belongs into the head of each
+// served page.
+const ReloadScript template.HTML = `<script>new EventSource("/events").addEventListener("reload", () => location.reload())</script>`
+
+// Server serves the latest page at /, its search files, the reload events at /events and the json api at /api.
+type Server struct {
+	mutex   sync.RWMutex
+	page    []byte
+	idx     *Index
+	entries []search.Entry // the client-side search index
+	clients map[chan struct{}]struct{}
+	mux     *http.ServeMux
+}
+
+func New() *Server {
+	s := &Server{clients: map[chan struct{}]struct{}{}, mux: http.NewServeMux(), idx: NewIndex(&api.Module{})}
+	s.mux.HandleFunc("/", s.home)
+	s.mux.HandleFunc("/events", s.events)
+	s.mux.HandleFunc("/"+search.IndexFile, s.searchIndex)
+	s.mux.HandleFunc("/"+search.ScriptFile, s.searchScript)
+	s.mux.HandleFunc("/api/packages", s.packages)
+	s.mux.HandleFunc("/api/symbol/", s.symbol)
+	s.mux.HandleFunc("/api/search", s.search)
+	s.mux.HandleFunc("/api/implementers/", s.implementers)
+	return s
+}
+
+// Update publishes a new version of the module and its page and tells all connected browsers to reload.
+func (s *Server) Update(m *api.Module, page []byte) {
+	idx := NewIndex(m)
+	entries := search.Index(m)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.page = page
+	s.idx = idx
+	s.entries = entries
+	s.notify()
+}
+
+// Fail replaces the page by the error, until the next Update. Browsers reload as well, but the api keeps the last
+// module.
+func (s *Server) Fail(err error) {
+	page := []byte(fmt.Sprintf("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n%s\n</head>\n<body>\n<pre>%s</pre>\n</body>\n</html>\n",
+		ReloadScript, html.EscapeString(err.Error())))
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.page = page
+	s.notify()
+}
+
+// notify tells all connected browsers to reload. The caller must hold the lock.
+func (s *Server) notify() {
+	for c := range s.clients {
+		select {
+		case c <- struct{}{}:
+		default:
+			// a reload is already pending
+		}
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// index returns the index of the current module.
+func (s *Server) index() *Index {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.idx
+}
+
+// home serves the current page.
+func (s *Server) home(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" && r.URL.Path != "/index.html" {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mutex.RLock()
+	page := s.page
+	s.mutex.RUnlock()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(page)
+}
+
+// searchIndex serves the index of the client-side search.
+func (s *Server) searchIndex(w http.ResponseWriter, r *http.Request) {
+	s.mutex.RLock()
+	entries := s.entries
+	s.mutex.RUnlock()
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// searchScript serves the script of the client-side search.
+func (s *Server) searchScript(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+	_, _ = w.Write(search.Script)
+}
+
+// events streams a reload event for each update, until the browser disconnects.
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	c := make(chan struct{}, 1)
+	s.mutex.Lock()
+	s.clients[c] = struct{}{}
+	s.mutex.Unlock()
+
+	defer func() {
+		s.mutex.Lock()
+		delete(s.clients, c)
+		s.mutex.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-c:
+			_, _ = fmt.Fprint(w, "event: reload\ndata: \n\n")
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package doclint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/analysis"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// docLink matches a doc link to a name, which may be qualified by a package and a type.
+	docLink = regexp.MustCompile(`\[(\*?[A-Za-z_]\w*(?:\.[A-Za-z_]\w*){0,2})\]`)
+	// listMarker matches the start of a list item.
+	listMarker = regexp.MustCompile(`^(?:[-*+•]|\d+[.)])[ \t]+\S`)
+)
+
+// checkComment reports unresolvable doc links and lists or code blocks, which go doc does not recognize.
+func checkComment(pass *analysis.Pass, doc *ast.CommentGroup) {
+	if doc == nil {
+		return
+	}
+
+	prev := "" // the previous line, which is empty at the start of a paragraph
+	for _, l := range lines(doc) {
+		indented := strings.HasPrefix(l.text, " ") || strings.HasPrefix(l.text, "\t")
+		blank := strings.TrimSpace(l.text) == ""
+
+		switch {
+		case blank:
+		case indented:
+			if prev != "" && !strings.HasPrefix(prev, " ") && !strings.HasPrefix(prev, "\t") && !listMarker.MatchString(prev) {
+				reportBlock(pass, l)
+			}
+		case listMarker.MatchString(l.text):
+			pass.Report(analysis.Diagnostic{
+				Pos:     l.pos,
+				Message: "list item is not indented and renders as text",
+				SuggestedFixes: []analysis.SuggestedFix{{
+					Message:   "indent the list item",
+					TextEdits: []analysis.TextEdit{{Pos: l.pos, End: l.pos, NewText: []byte("  ")}},
+				}},
+			})
+			checkLinks(pass, l)
+		default:
+			checkLinks(pass, l)
+		}
+
+		if blank {
+			prev = ""
+		} else {
+			prev = l.text
+		}
+	}
+}
+
+// reportBlock reports an indented block, which directly follows a paragraph. The fix inserts an empty comment
+// line with the indentation of the block. Only the first comment of a group can follow code on the same line, so
+// the block is preceded by its indentation, which gofmt writes as tabs.
+func reportBlock(pass *analysis.Pass, l line) {
+	indent := strings.Repeat("\t", pass.Fset.Position(l.c.Slash).Column-1)
+	pass.Report(analysis.Diagnostic{
+		Pos:     l.pos,
+		Message: "code block should be separated from the paragraph by an empty line",
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message:   "insert an empty line",
+			TextEdits: []analysis.TextEdit{{Pos: l.c.Slash, End: l.c.Slash, NewText: []byte("//\n" + indent)}},
+		}},
+	})
+}
+
+// checkLinks reports doc links, which gdoc cannot resolve. Like go doc, brackets after a word or before a
+// parenthesis or colon are not links, e.g. an index expression or a markdown link.
+func checkLinks(pass *analysis.Pass, l line) {
+	if strings.HasPrefix(l.text, "[") && strings.Contains(l.text, "]:") {
+		// the definition of a link like [text]: https://...
+		return
+	}
+
+	for _, m := range docLink.FindAllStringSubmatchIndex(l.text, -1) {
+		before, after := rune(' '), rune(' ')
+		if m[0] > 0 {
+			before = rune(l.text[m[0]-1])
+		}
+		if m[1] < len(l.text) {
+			after = rune(l.text[m[1]])
+		}
+
+		if isWord(before) || strings.ContainsRune("[]", before) || isWord(after) || strings.ContainsRune("(:[", after) {
+			continue
+		}
+
+		name := l.text[m[2]:m[3]]
+		if qualifier, _, ok := strings.Cut(name, "."); ok && lookupPackage(pass, qualifier) == nil &&
+			pass.Pkg.Scope().Lookup(qualifier) == nil {
+			// a link to a package, which is not imported, like [io.Reader]
+			continue
+		}
+
+		spaced := unicode.IsSpace(before) && unicode.IsSpace(after)
+		if spaced && resolves(pass, name) {
+			continue
+		}
+
+		pos := l.pos + token.Pos(m[2])
+		d := analysis.Diagnostic{
+			Pos:     pos,
+			End:     pos + token.Pos(len(name)),
+			Message: "doc link [" + name + "] cannot be resolved",
+		}
+
+		switch fix := similar(pass, name); {
+		case !spaced && resolves(pass, name):
+			d.Message = "doc link [" + name + "] must be surrounded by spaces"
+		case spaced && fix != "":
+			d.SuggestedFixes = []analysis.SuggestedFix{{
+				Message:   "link to " + fix,
+				TextEdits: []analysis.TextEdit{{Pos: d.Pos, End: d.End, NewText: []byte(fix)}},
+			}}
+		}
+
+		pass.Report(d)
+	}
+}
+
+// resolves returns true, if gdoc links the name, i.e. it is an exported constant, function or type of the package.
+// A name qualified like pkg.Name is looked up in the package or the import, whose name or last import path element
+// is pkg. Unlike go doc, gdoc does not link methods, fields or builtins.
+func resolves(pass *analysis.Pass, name string) bool {
+	scope := pass.Pkg.Scope()
+	if qualifier, rest, ok := strings.Cut(name, "."); ok {
+		pkg := lookupPackage(pass, qualifier)
+		if pkg == nil {
+			return false
+		}
+		scope, name = pkg.Scope(), rest
+	}
+
+	if !token.IsExported(name) {
+		return false
+	}
+
+	switch scope.Lookup(name).(type) {
+	case *types.Const, *types.Func, *types.TypeName:
+		return true
+	default:
+		return false
+	}
+}
+
+// lookupPackage returns the package or the import, whose name or last import path element is the qualifier, or nil.
+func lookupPackage(pass *analysis.Pass, qualifier string) *types.Package {
+	for _, pkg := range append([]*types.Package{pass.Pkg}, pass.Pkg.Imports()...) {
+		if pkg.Name() == qualifier || path.Base(pkg.Path()) == qualifier {
+			return pkg
+		}
+	}
+	return nil
+}
+
+func isWord(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// similar returns the linkable declaration of the package, whose name differs only by case or a leading star, or
+// an empty string.
+func similar(pass *analysis.Pass, name string) string {
+	if strings.Contains(name, ".") {
+		return ""
+	}
+
+	scope := pass.Pkg.Scope()
+	for _, n := range scope.Names() {
+		if strings.EqualFold(n, strings.TrimPrefix(name, "*")) && resolves(pass, n) {
+			return n
+		}
+	}
+	return ""
+}
@@ -0,0 +1,231 @@
+// Package doclint provides an analyzer, which checks doc comments for the conventions gdoc relies on. Run it
+// standalone with cmd/gdoclint or add Analyzer to a multichecker:
+//
+//	multichecker.Main(doclint.Analyzer, ...)
+package doclint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/analysis"
+	"strings"
+)
+
+const doc = `check doc comments of exported declarations
+
+The doclint analyzer reports exported declarations without doc comment, doc comments which do not start with the
+name of the declaration, doc links like [Name] which cannot be resolved, undocumented enum values and lists or
+code blocks, which go doc renders as plain text.`
+
+// Analyzer checks the doc comments of a package.
+var Analyzer = &analysis.Analyzer{
+	Name: "doclint",
+	Doc:  doc,
+	Run:  run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	for _, file := range pass.Files {
+		if strings.HasSuffix(pass.Fset.File(file.Pos()).Name(), "_test.go") || ast.IsGenerated(file) {
+			continue
+		}
+
+		checkComment(pass, file.Doc)
+
+		for _, d := range file.Decls {
+			switch d := d.(type) {
+			case *ast.FuncDecl:
+				checkFunc(pass, d)
+			case *ast.GenDecl:
+				checkGenDecl(pass, d)
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func checkFunc(pass *analysis.Pass, d *ast.FuncDecl) {
+	checkComment(pass, d.Doc)
+
+	if !d.Name.IsExported() || pass.Pkg.Name() == "main" {
+		return
+	}
+
+	name := d.Name.Name
+	if d.Recv != nil && len(d.Recv.List) > 0 {
+		recv := receiverName(d.Recv.List[0].Type)
+		if !ast.IsExported(recv) {
+			return
+		}
+		name = recv + "." + name
+	}
+
+	checkDoc(pass, d.Name, name, d.Doc)
+}
+
+func checkGenDecl(pass *analysis.Pass, d *ast.GenDecl) {
+	checkComment(pass, d.Doc)
+	grouped := d.Lparen.IsValid()
+
+	for _, spec := range d.Specs {
+		switch spec := spec.(type) {
+		case *ast.TypeSpec:
+			checkComment(pass, spec.Doc)
+			doc := spec.Doc
+			if !grouped {
+				doc = d.Doc
+			}
+
+			if spec.Name.IsExported() && pass.Pkg.Name() != "main" {
+				checkDoc(pass, spec.Name, spec.Name.Name, doc)
+			}
+
+			checkMembers(pass, spec)
+		case *ast.ValueSpec:
+			checkComment(pass, spec.Doc)
+			checkComment(pass, spec.Comment)
+			checkValue(pass, d, spec)
+		}
+	}
+}
+
+// checkValue checks a constant or variable. Single specs are documented like functions, grouped specs may be
+// documented by their group, except enum values.
+func checkValue(pass *analysis.Pass, d *ast.GenDecl, spec *ast.ValueSpec) {
+	if pass.Pkg.Name() == "main" {
+		return
+	}
+
+	for _, name := range spec.Names {
+		if !name.IsExported() {
+			continue
+		}
+
+		switch {
+		case !d.Lparen.IsValid():
+			checkDoc(pass, name, name.Name, d.Doc)
+		case spec.Doc != nil || spec.Comment != nil:
+			// documented individually
+		case enumValue(pass, name):
+			pass.Reportf(name.Pos(), "enum value %s should have a comment", name.Name)
+		case d.Doc == nil:
+			pass.Reportf(name.Pos(), "exported %s %s should have a comment or be in a commented group", d.Tok, name.Name)
+		}
+	}
+}
+
+// enumValue returns true for constants of a named type, which is declared in the same package.
+func enumValue(pass *analysis.Pass, name *ast.Ident) bool {
+	c, ok := pass.TypesInfo.Defs[name].(*types.Const)
+	if !ok {
+		return false
+	}
+
+	named, ok := c.Type().(*types.Named)
+	return ok && named.Obj().Pkg() == pass.Pkg
+}
+
+// checkMembers checks the comments of struct fields and interface methods for layout errors. Exported members
+// are not required to be documented.
+func checkMembers(pass *analysis.Pass, spec *ast.TypeSpec) {
+	var fields *ast.FieldList
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		fields = t.Fields
+	case *ast.InterfaceType:
+		fields = t.Methods
+	}
+
+	if fields == nil {
+		return
+	}
+
+	for _, f := range fields.List {
+		checkComment(pass, f.Doc)
+		checkComment(pass, f.Comment)
+	}
+}
+
+// checkDoc reports a missing doc comment or one, which does not start with the name of the declaration. Methods
+// are named like Type.Method but their comments start with the method name.
+func checkDoc(pass *analysis.Pass, ident *ast.Ident, name string, doc *ast.CommentGroup) {
+	if doc == nil || strings.TrimSpace(doc.Text()) == "" {
+		pass.Reportf(ident.Pos(), "exported %s should have a comment", name)
+		return
+	}
+
+	text := doc.Text()
+	for _, article := range []string{"A ", "An ", "The "} {
+		if rest, ok := strings.CutPrefix(text, article); ok && strings.HasPrefix(rest, ident.Name) {
+			return
+		}
+	}
+
+	if strings.HasPrefix(text, ident.Name) || strings.HasPrefix(text, "Deprecated:") {
+		return
+	}
+
+	first := doc.List[0]
+	if !strings.HasPrefix(first.Text, "//") || strings.HasPrefix(first.Text, "//go:") {
+		pass.Reportf(doc.Pos(), "comment on exported %s should be of the form \"%s ...\"", name, ident.Name)
+		return
+	}
+
+	// insert the name in front of the first word, e.g. "returns ..." becomes "Name returns ..."
+	pos := first.Slash + 2
+	if strings.HasPrefix(first.Text, "// ") {
+		pos++
+	}
+
+	pass.Report(analysis.Diagnostic{
+		Pos:     doc.Pos(),
+		End:     doc.End(),
+		Message: "comment on exported " + name + " should be of the form \"" + ident.Name + " ...\"",
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message:   "start the comment with " + ident.Name,
+			TextEdits: []analysis.TextEdit{{Pos: pos, End: pos, NewText: []byte(ident.Name + " ")}},
+		}},
+	})
+}
+
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(t.X)
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	case *ast.IndexListExpr:
+		return receiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// line is a single line of a // comment group together with the position of its text.
+type line struct {
+	text string
+	pos  token.Pos // the position of text
+	c    *ast.Comment
+}
+
+// lines splits a comment group into its lines. Block comments are ignored, because their layout is free.
+func lines(doc *ast.CommentGroup) []line {
+	var res []line
+	for _, c := range doc.List {
+		if !strings.HasPrefix(c.Text, "//") || strings.HasPrefix(c.Text, "//go:") || strings.HasPrefix(c.Text, "//gdoc:") {
+			continue
+		}
+
+		text := strings.TrimPrefix(c.Text, "//")
+		pos := c.Slash + 2
+		if strings.HasPrefix(text, " ") {
+			text = text[1:]
+			pos++
+		}
+		res = append(res, line{text: text, pos: pos, c: c})
+	}
+	return res
+}
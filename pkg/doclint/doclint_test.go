@@ -0,0 +1,11 @@
+package doclint_test
+
+import (
+	"github.com/worldiety/gdoc/pkg/doclint"
+	"golang.org/x/tools/go/analysis/analysistest"
+	"testing"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), doclint.Analyzer, "a")
+}
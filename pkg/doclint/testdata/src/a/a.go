@@ -0,0 +1,57 @@
+// Package a is checked by the doclint tests. It links to [Repo] and [NewRepo] but not to [repo] // want `doc link \[repo\] cannot be resolved`
+// Neither [Repo]. nor [Repo.Get] are linked by gdoc. // want `doc link \[Repo\] must be surrounded by spaces` `doc link \[Repo.Get\] cannot be resolved`
+// It links to [a.Repo] and [b.Type] but not to [b.Missing] and ignores [io.Reader]. // want `doc link \[b.Missing\] cannot be resolved`
+// Index expressions like r[i], [markdown](https://go.dev) and [Repo]: are no links.
+//
+// [Repo]: https://go.dev
+package a
+
+import "b"
+
+// Repo stores the entities:
+// - in memory // want `list item is not indented and renders as text`
+// - on disk // want `list item is not indented and renders as text`
+type Repo struct {
+	// Name is the name of the repository like
+	//	inmem // want `code block should be separated from the paragraph by an empty line`
+	Name string
+}
+
+// Get returns the entity of the [*Repo] and not of the [Repo]. // want `doc link \[\*Repo\] cannot be resolved` `doc link \[Repo\] must be surrounded by spaces`
+func (r *Repo) Get() {}
+
+// returns the size. // want `comment on exported Repo.Size should be of the form "Size ..."`
+func (r *Repo) Size() {}
+
+func (r *Repo) Close() {} // want `exported Repo.Close should have a comment`
+
+/* creates a repository. // want `comment on exported NewRepo should be of the form "NewRepo ..."` */
+func NewRepo() *Repo { return nil }
+
+// Open opens the [newrepo] of the package. // want `doc link \[newrepo\] cannot be resolved`
+func Open() {}
+
+func Delete() {} // want `exported Delete should have a comment`
+
+// Mode is the [Repo] access mode.
+type Mode string
+
+// want +5 `enum value ReadWrite should have a comment`
+
+const (
+	// ReadOnly forbids writes.
+	ReadOnly  Mode = "r"
+	ReadWrite Mode = "rw"
+)
+
+// want +3 `exported var Strict should have a comment or be in a commented group`
+
+var (
+	Strict bool
+)
+
+// Index indexes the entities by [b.Type] values.
+var Index map[b.Type]Repo
+
+// Default is the default mode.
+var Default = ReadOnly
@@ -0,0 +1,5 @@
+// Package b is imported by package a to test links to other packages.
+package b
+
+// Type is linked by package a.
+type Type int
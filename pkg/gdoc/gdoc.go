@@ -0,0 +1,148 @@
+// Package gdoc parses and resolves a Go module into a documentation model and renders it with pluggable
+// generators. It is the library behind cmd/gdoc for tools, which build custom reports or integrate gdoc into a build:
+//
+//	m, err := gdoc.Load(ctx, gdoc.Options{ModPath: ".", CallDepth: 1})
+//	if err != nil {
+//		return err
+//	}
+//
+//	return gdoc.Write(ctx, gdoc.HTML(gdoc.Options{}), m, "out", "index.html")
+//
+// # Compatibility
+//
+// This package follows semantic versioning, unlike the internal packages of the module. Within a major version,
+// exported identifiers are neither removed nor changed incompatibly. The model types are declared by this package
+// and copied from the internal model by Load, so that they do not follow its changes. New fields may be added to
+// Options and to the model types, thus use keyed composite literals. The zero value of a new Options field keeps the
+// previous behavior. The rendered documents are not part of the guarantee and may change with any release.
+package gdoc
+
+import (
+	"context"
+	"fmt"
+	"github.com/worldiety/gdoc/internal/app"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+	"strings"
+)
+
+const (
+	// HashAnchors are stable but unreadable anchors like gd4f1c…, which is the default.
+	HashAnchors = app.HashAnchors
+	// ReadableAnchors are derived from the import path and the identifier, like internal-api--Symbol--Name.
+	ReadableAnchors = app.ReadableAnchors
+
+	// CallGraphCHA is the class hierarchy analysis, which is sound but imprecise for dynamic calls. It is the default.
+	CallGraphCHA = golang.CallGraphCHA
+	// CallGraphVTA is the variable type analysis, which refines the CallGraphCHA result.
+	CallGraphVTA = golang.CallGraphVTA
+)
+
+// Options configure Load and the built-in generators. The zero value loads all packages of the module, which
+// contains the working directory.
+type Options struct {
+	// ModPath is the root directory of the module. If empty, the module of the working directory is used.
+	ModPath string
+	// Packages restricts the parsed packages to the given import paths, if not empty.
+	Packages []string
+	// Strict fails on unresolved comment links, missing anchors and unknown types instead of returning them as the
+	// Diagnostics of the module.
+	Strict bool
+	// Anchors is either HashAnchors or ReadableAnchors. Load builds the anchors of the returned module, see Module
+	// Anchor, and the built-in generators render them regardless of their own Options.
+	Anchors string
+	// Since annotates each declaration with the first semver tag, which declares it. Requires git.
+	Since bool
+	// CacheDir caches the parsed tags for Since. If empty, nothing is cached.
+	CacheDir string
+	// CallDepth documents the calls between exported functions up to the given depth, if greater than 0.
+	CallDepth int
+	// CallGraph is either CallGraphCHA or CallGraphVTA.
+	CallGraph string
+	// Source links each declaration to its source, either github, gitlab, gitea, bitbucket, file or a url template
+	// with the placeholders {repo}, {commit}, {root}, {path}, {line} and {endLine}.
+	Source string
+	// SourceRepo is the web url of the repository for Source. If empty, it is derived from the origin remote.
+	SourceRepo string
+	// Bodies embeds the source of the functions and methods, which match one of the import path prefixes or
+	// stereotypes. * matches all.
+	Bodies []string
+
+	// Diagrams embeds dot, plantuml, mermaid or built-in svg diagrams, if not empty.
+	Diagrams string
+	// GraphCollapse collapses the packages below each import path prefix into a single node of the import graph.
+	GraphCollapse []string
+	// GraphStdlib includes the standard library in the import graph.
+	GraphStdlib bool
+	// GraphExternal includes the packages of other modules in the import graph.
+	GraphExternal bool
+	// GraphCycles highlights import cycles.
+	GraphCycles bool
+	// Templates overrides or extends the asciidoc templates by the *.tmpl files of this directory, if not empty.
+	Templates string
+	// PkgSep replaces the / of the import paths in the JSON and YAML output, if not empty.
+	PkgSep string
+}
+
+// config returns the configuration of the internal implementation.
+func (o Options) config(format string) (app.Config, error) {
+	cfg := app.Config{
+		ModPath:       o.ModPath,
+		OutputFormat:  format,
+		Packages:      strings.Join(o.Packages, ";"),
+		PkgSep:        o.PkgSep,
+		Diagrams:      o.Diagrams,
+		GraphCollapse: strings.Join(o.GraphCollapse, ";"),
+		GraphStdlib:   o.GraphStdlib,
+		GraphExternal: o.GraphExternal,
+		GraphCycles:   o.GraphCycles,
+		CallDepth:     o.CallDepth,
+		CallGraph:     o.CallGraph,
+		Since:         o.Since,
+		CacheDir:      o.CacheDir,
+		Strict:        o.Strict,
+		Source:        o.Source,
+		SourceRepo:    o.SourceRepo,
+		Bodies:        strings.Join(o.Bodies, ";"),
+		Anchors:       o.Anchors,
+		Templates:     o.Templates,
+	}
+
+	if cfg.ModPath == "" {
+		wd, err := golang.ModWdRoot()
+		if err != nil {
+			return cfg, fmt.Errorf("could not walk to mod root directory: %w", err)
+		}
+		cfg.ModPath = wd
+	}
+
+	if cfg.PkgSep == "" {
+		cfg.PkgSep = "/"
+	}
+
+	if cfg.CallGraph == "" {
+		cfg.CallGraph = CallGraphCHA
+	}
+
+	if cfg.Anchors == "" {
+		cfg.Anchors = HashAnchors
+	}
+
+	return cfg, nil
+}
+
+// Load parses and resolves the module. It stops with the error of the context, as soon as it is done, which is
+// checked between the parsing and the resolving steps. Load does not log, the unresolved references are returned as
+// the Diagnostics of the module.
+func Load(ctx context.Context, opts Options) (*Module, error) {
+	cfg, err := opts.config("")
+	if err != nil {
+		return nil, err
+	}
+
+	node, diagnostics, err := app.LoadContext(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return newModule(node, diagnostics), nil
+}
@@ -0,0 +1,49 @@
+package gdoc_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/worldiety/gdoc/internal/fixture"
+	"github.com/worldiety/gdoc/pkg/gdoc"
+	"strings"
+)
+
+func ExampleGeneratorFunc() {
+	m, err := gdoc.Load(context.Background(), gdoc.Options{ModPath: fixture.Dir(), Anchors: gdoc.ReadableAnchors})
+	if err != nil {
+		panic(err)
+	}
+
+	report := gdoc.GeneratorFunc(func(ctx context.Context, m *gdoc.Module) ([]byte, error) {
+		var sb strings.Builder
+		for _, s := range m.Symbols() {
+			if s.Package == fixture.Name+"/store" {
+				fmt.Fprintf(&sb, "%s %s #%s\n", s.Kind, s.Name, m.Anchor(s.Ref))
+			}
+		}
+
+		return []byte(sb.String()), nil
+	})
+
+	buf, err := report.Generate(context.Background(), m)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(buf))
+	for _, d := range m.Diagnostics {
+		fmt.Println(d.Kind, d)
+	}
+	// Output:
+	// const Append #store--Append
+	// var DefaultMode #store--DefaultMode
+	// func ExampleNewStore #store--ExampleNewStore
+	// type Mode #store--Mode
+	// func NewStore #store--NewStore
+	// const ReadOnly #store--ReadOnly
+	// const ReadWrite #store--ReadWrite
+	// type Store #store--Store
+	// field Store.Mode #store--Store--Mode
+	// var Strict #store--Strict
+	// unresolved-link store/mode.go:6:6: unresolved link [Tenant] in the comment of store.Mode
+}
@@ -0,0 +1,126 @@
+package gdoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/worldiety/gdoc/internal/app"
+	"os"
+	"path/filepath"
+)
+
+// Generator renders a loaded module into a single document. Implement it for custom reports, the built-in
+// generators are Asciidoc, HTML, JSON and YAML.
+type Generator interface {
+	Generate(ctx context.Context, m *Module) ([]byte, error)
+}
+
+// FileGenerator is a Generator, whose document requires additional standalone files next to it, like diagrams, the
+// search index or the map of the legacy anchors.
+type FileGenerator interface {
+	Generator
+	// Files returns the content of each file by its slash separated path relative to the document.
+	Files(ctx context.Context, m *Module) (map[string][]byte, error)
+}
+
+// GeneratorFunc adapts a function to a Generator.
+type GeneratorFunc func(ctx context.Context, m *Module) ([]byte, error)
+
+// Generate calls f.
+func (f GeneratorFunc) Generate(ctx context.Context, m *Module) ([]byte, error) {
+	return f(ctx, m)
+}
+
+// errNotLoaded is returned by the built-in generators for a module, which was not returned by Load.
+var errNotLoaded = errors.New("the module was not loaded by Load")
+
+// format is a built-in generator of cmd/gdoc.
+type format struct {
+	opts   Options
+	format string
+}
+
+// Asciidoc renders the module as asciidoc document for asciidoctor, see Options Templates for a custom layout.
+func Asciidoc(opts Options) FileGenerator {
+	return format{opts: opts, format: app.Adoc}
+}
+
+// HTML renders the module as self-contained html page, which does not require asciidoctor.
+func HTML(opts Options) FileGenerator {
+	return format{opts: opts, format: app.Native}
+}
+
+// JSON renders the model of the module as json.
+func JSON(opts Options) FileGenerator {
+	return format{opts: opts, format: app.Json}
+}
+
+// YAML renders the model of the module as yaml.
+func YAML(opts Options) FileGenerator {
+	return format{opts: opts, format: app.Yaml}
+}
+
+func (f format) Generate(ctx context.Context, m *Module) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if m.node == nil {
+		return nil, errNotLoaded
+	}
+
+	cfg, err := f.opts.config(f.format)
+	if err != nil {
+		return nil, err
+	}
+
+	return app.Render(cfg, m.node)
+}
+
+func (f format) Files(ctx context.Context, m *Module) (map[string][]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if m.node == nil {
+		return nil, errNotLoaded
+	}
+
+	cfg, err := f.opts.config(f.format)
+	if err != nil {
+		return nil, err
+	}
+
+	return app.Files(cfg, m.node)
+}
+
+// Write generates the document of the module into the file name of dir. The files of a FileGenerator are written
+// relative to it, while missing directories are created.
+func Write(ctx context.Context, g Generator, m *Module, dir, name string) error {
+	buf, err := g.Generate(ctx, m)
+	if err != nil {
+		return fmt.Errorf("cannot generate %s: %w", name, err)
+	}
+
+	files := map[string][]byte{}
+	if fg, ok := g.(FileGenerator); ok {
+		files, err = fg.Files(ctx, m)
+		if err != nil {
+			return fmt.Errorf("cannot generate the files of %s: %w", name, err)
+		}
+	}
+
+	files[name] = buf
+	for name, content := range files {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			return fmt.Errorf("cannot create directory: %w", err)
+		}
+
+		if err := os.WriteFile(name, content, 0644); err != nil {
+			return fmt.Errorf("cannot write %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,458 @@
+package gdoc
+
+import (
+	"fmt"
+	"github.com/worldiety/gdoc/internal/api"
+	"github.com/worldiety/gdoc/internal/parser/golang"
+	"sort"
+	"strings"
+)
+
+// Module is the documentation model of a Go module. Load returns it together with the internal model, which the
+// built-in generators render. A Module built by hand can only be rendered by custom generators.
+type Module struct {
+	Name        string
+	Readme      string
+	Packages    map[ImportPath]*Package
+	Glossary    []Term
+	Diagnostics []Diagnostic // the unresolved references found by Load, see Options Strict
+
+	node *api.Module
+}
+
+// ImportPath is the import path of a package like example.com/m/store.
+type ImportPath = string
+
+// RefId identifies a declaration of a package. Methods and fields are identified like Type.Name.
+type RefId struct {
+	ImportPath ImportPath
+	Identifier string
+}
+
+// NewRefID returns the identity of the declaration of identifier in the package of importPath. Methods and fields
+// are identified like Type.Name.
+func NewRefID(importPath, identifier string) RefId {
+	return RefId{ImportPath: importPath, Identifier: identifier}
+}
+
+// Package is a documented package of the module.
+type Package struct {
+	ImportPath ImportPath
+	Name       string
+	Doc        string
+	Readme     string
+	Imports    []ImportPath
+	Deprecated *Deprecation
+	Position   Position // the package clause of the documented file
+	Consts     []ConstantBlock
+	Vars       map[string]*Variable
+	Functions  map[string]*Function // the functions, which are not a constructor of one of the Structs
+	Structs    map[string]*Struct   // all declared types, not only structs
+}
+
+// Struct is a declared type. Interfaces list their method set as Methods.
+type Struct struct {
+	TypeDefinition RefId
+	Name           string
+	Comment        string
+	Interface      bool
+	Fields         []*Field
+	Methods        []*Function
+	Constructors   []*Function
+	Implements     []RefId // the interfaces declared in the module, which are realised by this type
+	ReferencedBy   []Reference
+	Since          string // the first release, which declares this type
+	Deprecated     *Deprecation
+	Position       Position
+}
+
+// Field is a field of a struct. Embedded fields have no name.
+type Field struct {
+	Definition RefId
+	Name       string
+	Type       string // the type as written in the source, like *Store or map[string]int
+	Doc        string
+	Comment    string
+	Since      string
+	Deprecated *Deprecation
+	Position   Position
+}
+
+// Function is a function or a method.
+type Function struct {
+	TypeDefinition RefId
+	Name           string
+	Recv           string // the receiver type of a method like *Store, empty for functions
+	Comment        string
+	Signature      string    // the declaration without the func keyword and the receiver
+	Calls          []CallRef // the exported functions and methods of the module, which are called by this one
+	CalledBy       []CallRef // the exported functions and methods of the module, which call this one
+	Since          string
+	Deprecated     *Deprecation
+	Position       Position
+}
+
+// CallRef is a call to or from another function. Depth is 1 for a direct call.
+type CallRef struct {
+	Target RefId
+	Depth  int
+}
+
+// Variable is a package level variable.
+type Variable struct {
+	Definition RefId
+	Name       string
+	Type       string // the declared or inferred type, empty if unknown
+	Doc        string
+	Comment    string
+	Since      string
+	Deprecated *Deprecation
+	Position   Position
+}
+
+// ConstantBlock is a const declaration, whose Doc documents all of its constants.
+type ConstantBlock struct {
+	Doc       string
+	Constants []*Constant
+}
+
+// Constant is a single constant of a ConstantBlock.
+type Constant struct {
+	Definition RefId
+	Name       string
+	Value      string // the value as written in the source, empty for implicit repetitions like iota sequences
+	Comment    string
+	Since      string
+	Deprecated *Deprecation
+	Position   Position
+}
+
+// Reference denotes a single usage of a type. From is the declaring struct, function, method or variable and Name is
+// the name of the field, parameter, result or type parameter. Kind is one of property, embedded, parameter, result,
+// generic or variable.
+type Reference struct {
+	From RefId
+	Kind string
+	Name string
+}
+
+// Position locates a declaration in the source. File is relative to the module root and uses forward slashes.
+// URL links to the declaration on a code host, if configured.
+type Position struct {
+	File    string
+	Line    int
+	Column  int
+	EndLine int
+	URL     string
+}
+
+// String formats the position like the go tools do, e.g. store/store.go:12:6.
+func (p Position) String() string {
+	if p.Line == 0 {
+		return p.File
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// Deprecation is the content of a Deprecated: paragraph of a doc comment. Notice usually names the replacement
+// and Since is the first release, which deprecates the declaration.
+type Deprecation struct {
+	Notice string
+	Since  string
+}
+
+// Term is an entry of the glossary, either from a glossary file or from a //gdoc:term directive.
+type Term struct {
+	Name       string
+	Definition string
+}
+
+// Diagnostic is an unresolved reference of the documentation, which is located at the declaration causing it. Kind
+// is one of unresolved-link, missing-anchor or unknown-type.
+type Diagnostic struct {
+	Kind     string
+	Position Position
+	Package  ImportPath
+	Symbol   string // the name of the declaration like Type.Method, empty for the package
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	pos := d.Position.String()
+	if pos == "" {
+		pos = d.Package
+	}
+	return pos + ": " + d.Message
+}
+
+// SymbolKind classifies the declaration of a Symbol.
+type SymbolKind string
+
+// The kinds of a Symbol, see Module Symbols.
+const (
+	SymbolType   SymbolKind = "type"
+	SymbolFunc   SymbolKind = "func"
+	SymbolMethod SymbolKind = "method"
+	SymbolField  SymbolKind = "field"
+	SymbolConst  SymbolKind = "const"
+	SymbolVar    SymbolKind = "var"
+)
+
+// Symbol is a single exported identifier of a module. Methods and fields are named like Type.Method.
+type Symbol struct {
+	Ref        RefId
+	Name       string
+	Kind       SymbolKind
+	Package    ImportPath
+	Deprecated *Deprecation
+}
+
+// Symbols returns all exported identifiers of the module, sorted case-insensitively by name and then by import path.
+func (m *Module) Symbols() []Symbol {
+	var res []Symbol
+	add := func(ref RefId, kind SymbolKind, deprecated *Deprecation) {
+		res = append(res, Symbol{Ref: ref, Name: ref.Identifier, Kind: kind, Package: ref.ImportPath, Deprecated: deprecated})
+	}
+
+	for _, p := range m.Packages {
+		for _, block := range p.Consts {
+			for _, c := range block.Constants {
+				add(c.Definition, SymbolConst, c.Deprecated)
+			}
+		}
+
+		for _, v := range p.Vars {
+			add(v.Definition, SymbolVar, v.Deprecated)
+		}
+
+		for _, fn := range p.Functions {
+			add(fn.TypeDefinition, SymbolFunc, fn.Deprecated)
+		}
+
+		for _, s := range p.Structs {
+			add(s.TypeDefinition, SymbolType, s.Deprecated)
+			for _, fn := range s.Constructors {
+				add(fn.TypeDefinition, SymbolFunc, fn.Deprecated)
+			}
+			for _, method := range s.Methods {
+				add(method.TypeDefinition, SymbolMethod, method.Deprecated)
+			}
+			for _, f := range s.Fields {
+				if f.Name != "" {
+					add(f.Definition, SymbolField, f.Deprecated)
+				}
+			}
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		a, b := strings.ToLower(res[i].Name), strings.ToLower(res[j].Name)
+		if a != b {
+			return a < b
+		}
+		if res[i].Name != res[j].Name {
+			return res[i].Name < res[j].Name
+		}
+		return res[i].Package < res[j].Package
+	})
+
+	return res
+}
+
+// Anchor returns the id of the declaration in the documents of the built-in generators, see Options Anchors. The
+// anchor of a package is the one of its import path without identifier.
+func (m *Module) Anchor(ref RefId) string {
+	id := api.NewRefID(ref.ImportPath, ref.Identifier)
+	if m.node == nil {
+		return id.LegacyID()
+	}
+
+	return m.node.Anchor(id)
+}
+
+// newModule copies the internal model into the public one.
+func newModule(node *api.Module, diagnostics []golang.Diagnostic) *Module {
+	m := &Module{
+		Name:     node.Name,
+		Readme:   node.Readme,
+		Packages: map[ImportPath]*Package{},
+		node:     node,
+	}
+
+	for _, t := range node.Glossary {
+		m.Glossary = append(m.Glossary, Term{Name: t.Name, Definition: t.Definition})
+	}
+
+	for _, d := range diagnostics {
+		m.Diagnostics = append(m.Diagnostics, Diagnostic{
+			Kind:     string(d.Kind),
+			Position: newPosition(d.Position),
+			Package:  d.Package,
+			Symbol:   d.Symbol,
+			Message:  d.Message,
+		})
+	}
+
+	for path, p := range node.Packages {
+		m.Packages[path] = newPackage(path, p)
+	}
+
+	return m
+}
+
+func newPackage(path ImportPath, p *api.Package) *Package {
+	res := &Package{
+		ImportPath: path,
+		Name:       p.Name,
+		Doc:        p.Doc,
+		Readme:     p.Readme,
+		Deprecated: newDeprecation(p.Deprecated),
+		Position:   newPosition(p.Position),
+		Vars:       map[string]*Variable{},
+		Functions:  map[string]*Function{},
+		Structs:    map[string]*Struct{},
+	}
+
+	for _, imp := range p.Imports {
+		res.Imports = append(res.Imports, string(imp))
+	}
+
+	for _, block := range p.Consts {
+		b := ConstantBlock{Doc: block.Doc}
+		for _, c := range block.Content {
+			b.Constants = append(b.Constants, &Constant{
+				Definition: newRefID(c.RefId),
+				Name:       c.RefId.Identifier,
+				Value:      c.Expr,
+				Comment:    trim(c.Comment),
+				Since:      c.Since,
+				Deprecated: newDeprecation(c.Deprecated),
+				Position:   newPosition(c.Position),
+			})
+		}
+		res.Consts = append(res.Consts, b)
+	}
+
+	for name, v := range p.Vars {
+		res.Vars[name] = &Variable{
+			Definition: newRefID(v.Definition),
+			Name:       v.Name,
+			Type:       typeString(v.TypeDesc),
+			Doc:        v.Doc,
+			Comment:    trim(v.Comment),
+			Since:      v.Since,
+			Deprecated: newDeprecation(v.Deprecated),
+			Position:   newPosition(v.Position),
+		}
+	}
+
+	for name, fn := range p.Functions {
+		res.Functions[name] = newFunction(fn, "")
+	}
+
+	for name, s := range p.Structs {
+		res.Structs[name] = newStruct(s)
+	}
+
+	return res
+}
+
+func newStruct(s *api.Struct) *Struct {
+	res := &Struct{
+		TypeDefinition: newRefID(s.TypeDefinition),
+		Name:           s.Name,
+		Comment:        s.Comment,
+		Interface:      s.Interface(),
+		Since:          s.Since,
+		Deprecated:     newDeprecation(s.Deprecated),
+		Position:       newPosition(s.Position),
+	}
+
+	for _, f := range s.Fields {
+		res.Fields = append(res.Fields, &Field{
+			Definition: newRefID(f.Definition),
+			Name:       f.Name,
+			Type:       typeString(f.TypeDesc),
+			Doc:        f.Doc,
+			Comment:    trim(f.Comment),
+			Since:      f.Since,
+			Deprecated: newDeprecation(f.Deprecated),
+			Position:   newPosition(f.Position),
+		})
+	}
+
+	for _, m := range s.Methods {
+		var recv string
+		if m.Recv != nil {
+			recv = m.Recv.TypeString
+		}
+		res.Methods = append(res.Methods, newFunction(m.Function, recv))
+	}
+
+	for _, fn := range s.Constructors {
+		res.Constructors = append(res.Constructors, newFunction(fn, ""))
+	}
+
+	for _, ref := range s.Implements {
+		res.Implements = append(res.Implements, newRefID(ref))
+	}
+
+	for _, r := range s.ReferencedBy {
+		res.ReferencedBy = append(res.ReferencedBy, Reference{From: newRefID(r.From), Kind: string(r.Kind), Name: r.Name})
+	}
+
+	return res
+}
+
+func newFunction(fn *api.Function, recv string) *Function {
+	res := &Function{
+		TypeDefinition: newRefID(fn.TypeDefinition),
+		Name:           fn.Name,
+		Recv:           recv,
+		Comment:        fn.Comment,
+		Signature:      fn.Signature,
+		Since:          fn.Since,
+		Deprecated:     newDeprecation(fn.Deprecated),
+		Position:       newPosition(fn.Position),
+	}
+
+	for _, c := range fn.Calls {
+		res.Calls = append(res.Calls, CallRef{Target: newRefID(c.Target), Depth: c.Depth})
+	}
+
+	for _, c := range fn.CalledBy {
+		res.CalledBy = append(res.CalledBy, CallRef{Target: newRefID(c.Target), Depth: c.Depth})
+	}
+
+	return res
+}
+
+func newRefID(id api.RefId) RefId {
+	return RefId{ImportPath: id.ImportPath, Identifier: id.Identifier}
+}
+
+func newPosition(p api.Position) Position {
+	return Position{File: p.File, Line: p.Line, Column: p.Column, EndLine: p.EndLine, URL: p.URL}
+}
+
+func newDeprecation(d *api.Deprecation) *Deprecation {
+	if d == nil {
+		return nil
+	}
+
+	return &Deprecation{Notice: d.Notice, Since: d.Since}
+}
+
+// typeString returns the type as written in the source.
+func typeString(td *api.TypeDesc) string {
+	if td == nil {
+		return ""
+	}
+
+	return td.SrcTypeDefinition
+}
+
+// trim removes the line break, which the parser keeps at the end of line comments.
+func trim(comment string) string {
+	return strings.TrimSpace(comment)
+}
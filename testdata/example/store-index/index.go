@@ -0,0 +1,13 @@
+// Package storeindex is the former home of the index package.
+//
+// Deprecated: use example.com/m/store/index instead.
+package storeindex
+
+import "example.com/m/store/index"
+
+// Index returns the name of the indexed field.
+//
+// Deprecated: use the Field of the Index in store/index instead.
+func Index() string {
+	return index.Index{Field: "id"}.Field
+}
@@ -0,0 +1,9 @@
+// Package index looks up the entities of a store by other fields than their id.
+package index
+
+//gdoc:term Tenant: the owner of an index, which loses against the one of package store
+
+// Index maps the values of a field to the ids of the entities.
+type Index struct {
+	Field string // the name of the indexed field
+}
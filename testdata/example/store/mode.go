@@ -0,0 +1,19 @@
+package store
+
+// Mode controls, how a Store writes entities. The [Mode] of a [Tenant] is looked up by
+//
+//	modes[Tenant]
+type Mode int
+
+// The modes of a Store.
+const (
+	// ReadOnly rejects all writes.
+	ReadOnly  Mode = iota
+	ReadWrite      // allows all writes
+	Append
+)
+
+// DefaultMode is used, if no mode is given.
+var DefaultMode Mode = ReadWrite
+
+var Strict Mode
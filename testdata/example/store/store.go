@@ -0,0 +1,16 @@
+// Package store keeps the entities of the example module.
+package store
+
+//gdoc:term Repository: a collection of entities, which can be looked up by their id.
+//gdoc:term Entity: anything with an id.
+//gdoc:term Tenant the owner of entities
+
+// Store keeps the entities in memory.
+type Store struct {
+	Mode Mode // controls the writes
+}
+
+// NewStore creates an empty Store.
+func NewStore(mode Mode) *Store {
+	return &Store{Mode: mode}
+}
@@ -0,0 +1,12 @@
+package store_test
+
+import (
+	"example.com/m/store"
+	"fmt"
+)
+
+func ExampleNewStore() {
+	s := store.NewStore(store.ReadOnly)
+	fmt.Println(s.Mode)
+	// Output: 0
+}